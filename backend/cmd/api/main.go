@@ -8,6 +8,7 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // event time zones must resolve in minimal images without zoneinfo
 
 	"planning-system/backend/internal/config"
 	"planning-system/backend/internal/db"
@@ -23,6 +24,9 @@ func main() {
 
 	cfg := config.Load()
 	logger := config.NewLogger(cfg)
+	if cfg.TokenSecret == "" {
+		logger.Fatal().Msg("TOKEN_SECRET is required outside dev")
+	}
 
 	// Database connection
 	pool, err := db.Connect(ctx, cfg, logger)
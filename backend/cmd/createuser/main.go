@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"planning-system/backend/internal/auth"
+	"planning-system/backend/internal/config"
+	"planning-system/backend/internal/db"
+	"planning-system/backend/internal/models"
+	"planning-system/backend/internal/repos"
+)
+
+// createuser provisions a login account, and optionally a new organization for it.
+//
+//	go run ./cmd/createuser -email ops@example.com -password secret -org <uuid>
+//	go run ./cmd/createuser -email ops@example.com -password secret -org-name "Acme Events"
+func main() {
+	email := flag.String("email", "", "login email (required)")
+	password := flag.String("password", "", "password (required)")
+	name := flag.String("name", "", "display name")
+	role := flag.String("role", auth.RoleAdmin, "admin | planner | staff | participant")
+	orgID := flag.String("org", "", "existing organization ID")
+	orgName := flag.String("org-name", "", "create a new organization with this name instead of -org")
+	participantID := flag.String("participant", "", "participant ID for participant users")
+	flag.Parse()
+
+	ctx := context.Background()
+	cfg := config.Load()
+	logger := config.NewLogger(cfg)
+
+	if *email == "" || *password == "" || (*orgID == "") == (*orgName == "") {
+		logger.Fatal().Msg("usage: createuser -email E -password P (-org ID | -org-name NAME) [-name N] [-role admin|planner|staff|participant] [-participant ID]")
+	}
+	if !auth.ValidRole(*role) {
+		logger.Fatal().Str("role", *role).Msg("role must be admin, planner, staff or participant")
+	}
+
+	pool, err := db.Connect(ctx, cfg, logger)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("db connect failed")
+	}
+	defer pool.Close()
+
+	if *orgName != "" {
+		org, err := repos.NewOrganizationsRepo(pool).Create(ctx, models.Organization{Name: *orgName})
+		if err != nil {
+			logger.Fatal().Err(err).Msg("create organization failed")
+		}
+		*orgID = org.ID
+		logger.Info().Str("organization_id", org.ID).Msg("organization created")
+	}
+
+	hash, err := auth.HashPassword(*password)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("hash password failed")
+	}
+	u := models.User{OrganizationID: *orgID, Email: *email, Name: *name, Role: *role, PasswordHash: hash}
+	if *participantID != "" {
+		u.ParticipantID = participantID
+	}
+	u, err = repos.NewUsersRepo(pool).Create(ctx, u)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("create user failed")
+	}
+	logger.Info().Str("user_id", u.ID).Str("email", u.Email).Msg("user created")
+}
@@ -0,0 +1,23 @@
+package auth
+
+import "context"
+
+// Audience decides which fields of the schedule a caller may see.
+type Audience string
+
+const (
+	// AudienceStaff sees everything, including staff instructions and internal notes.
+	AudienceStaff Audience = "staff"
+	// AudienceGuest sees only guest-facing fields.
+	AudienceGuest Audience = "guest"
+)
+
+// AudienceFromContext returns the audience of the caller in ctx. Callers who may
+// read the whole itinerary are staff; everyone else (participant users and
+// participant access links) is a guest.
+func AudienceFromContext(ctx context.Context) Audience {
+	if Granted(ctx, PermItineraryRead) {
+		return AudienceStaff
+	}
+	return AudienceGuest
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ParticipantTokenPrefix marks participant access (magic-link) tokens.
+const ParticipantTokenPrefix = "pt_"
+
+// SignParticipantToken returns a token carrying the access token row ID and its
+// expiry, signed with HMAC-SHA256:
+//
+//	pt_<base64url(id || expiry)>.<base64url(hmac)>
+func SignParticipantToken(secret []byte, id string, expiresAt time.Time) (string, error) {
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return "", err
+	}
+	payload := make([]byte, 0, 24)
+	payload = append(payload, uid[:]...)
+	payload = binary.BigEndian.AppendUint64(payload, uint64(expiresAt.Unix()))
+	enc := base64.RawURLEncoding
+	return ParticipantTokenPrefix + enc.EncodeToString(payload) + "." + enc.EncodeToString(participantTokenMAC(secret, payload)), nil
+}
+
+// ParseParticipantToken verifies the signature and expiry of a participant token and
+// returns the access token row ID. Revocation must be checked by the caller.
+func ParseParticipantToken(secret []byte, token string) (string, error) {
+	body, ok := strings.CutPrefix(token, ParticipantTokenPrefix)
+	if !ok {
+		return "", ErrInvalidToken
+	}
+	payloadPart, sigPart, ok := strings.Cut(body, ".")
+	if !ok {
+		return "", ErrInvalidToken
+	}
+	enc := base64.RawURLEncoding
+	payload, err := enc.DecodeString(payloadPart)
+	if err != nil || len(payload) != 24 {
+		return "", ErrInvalidToken
+	}
+	sig, err := enc.DecodeString(sigPart)
+	if err != nil || !hmac.Equal(sig, participantTokenMAC(secret, payload)) {
+		return "", ErrInvalidToken
+	}
+	if time.Now().Unix() >= int64(binary.BigEndian.Uint64(payload[16:])) {
+		return "", ErrInvalidToken
+	}
+	id, err := uuid.FromBytes(payload[:16])
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	return id.String(), nil
+}
+
+func participantTokenMAC(secret, payload []byte) []byte {
+	m := hmac.New(sha256.New, secret)
+	m.Write([]byte("participant-token:"))
+	m.Write(payload)
+	return m.Sum(nil)
+}
@@ -0,0 +1,141 @@
+package auth
+
+import "context"
+
+// Permission is a scope string checked per route, e.g. "schedule:write".
+type Permission string
+
+const (
+	// PermRead allows every read endpoint of the organization.
+	PermRead Permission = "read"
+	// PermItineraryRead allows reading events, days, blocks, movements, itineraries, exports and agendas.
+	PermItineraryRead Permission = "itinerary:read"
+	// PermResourcesRead allows reading locations, vehicles, participants and block templates.
+	PermResourcesRead Permission = "resources:read"
+	// PermScheduleWrite allows creating, editing and deleting blocks and movements.
+	PermScheduleWrite Permission = "schedule:write"
+	// PermBlocksWrite allows creating, editing and deleting blocks.
+	PermBlocksWrite Permission = "blocks:write"
+	// PermMovementsWrite allows creating, editing and deleting movements.
+	PermMovementsWrite Permission = "movements:write"
+	// PermTemplatesWrite allows managing block templates.
+	PermTemplatesWrite Permission = "templates:write"
+	// PermEventsWrite allows managing events and their days.
+	PermEventsWrite Permission = "events:write"
+	// PermResourcesWrite allows managing locations, vehicles and participants.
+	PermResourcesWrite Permission = "resources:write"
+	// PermOwnAgenda allows reading the agenda of the caller's own participant record.
+	PermOwnAgenda Permission = "agenda:own"
+	// PermAuditRead allows reading the audit log.
+	PermAuditRead Permission = "audit:read"
+	// PermAPIKeysManage allows issuing and revoking API keys. It cannot be granted to a key.
+	PermAPIKeysManage Permission = "api-keys:manage"
+)
+
+// implied lists the narrower permissions included in a broader one.
+var implied = map[Permission][]Permission{
+	PermRead:          {PermItineraryRead, PermResourcesRead},
+	PermScheduleWrite: {PermBlocksWrite, PermMovementsWrite},
+}
+
+const (
+	RoleAdmin       = "admin"
+	RolePlanner     = "planner"
+	RoleStaff       = "staff"
+	RoleParticipant = "participant"
+)
+
+var rolePermissions = map[string][]Permission{
+	RoleAdmin:       {PermRead, PermScheduleWrite, PermTemplatesWrite, PermEventsWrite, PermResourcesWrite, PermOwnAgenda, PermAuditRead, PermAPIKeysManage},
+	RolePlanner:     {PermRead, PermScheduleWrite, PermTemplatesWrite, PermOwnAgenda},
+	RoleStaff:       {PermRead, PermOwnAgenda},
+	RoleParticipant: {PermOwnAgenda},
+}
+
+// Event membership roles (see EventScoped).
+const (
+	EventRolePlanner = "planner"
+	EventRoleViewer  = "viewer"
+)
+
+// eventScopedRoles only see the events they are members of. Staff keep read access
+// to every event.
+var eventScopedRoles = map[string]bool{RolePlanner: true}
+
+// apiKeyScopes are the permissions that may be granted to an API key.
+var apiKeyScopes = []Permission{
+	PermRead, PermItineraryRead, PermResourcesRead,
+	PermScheduleWrite, PermBlocksWrite, PermMovementsWrite,
+	PermTemplatesWrite, PermEventsWrite, PermResourcesWrite,
+	PermAuditRead,
+}
+
+// ValidRole reports whether role is a known user role.
+func ValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// RolePermissions returns the permissions granted to role.
+func RolePermissions(role string) []Permission {
+	return rolePermissions[role]
+}
+
+// HasPermission reports whether role grants p.
+func HasPermission(role string, p Permission) bool {
+	return grants(rolePermissions[role], p)
+}
+
+// EventScoped reports whether users with role only see events they are members of.
+func EventScoped(role string) bool {
+	return eventScopedRoles[role]
+}
+
+// ValidEventRole reports whether role is a known event membership role.
+func ValidEventRole(role string) bool {
+	return role == EventRolePlanner || role == EventRoleViewer
+}
+
+// APIKeyScopes returns the scopes that may be granted to an API key.
+func APIKeyScopes() []Permission {
+	return apiKeyScopes
+}
+
+// ValidAPIKeyScope reports whether scope may be granted to an API key.
+func ValidAPIKeyScope(scope string) bool {
+	for _, s := range apiKeyScopes {
+		if string(s) == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Granted reports whether the caller in ctx, a user or an API key, holds p.
+func Granted(ctx context.Context, p Permission) bool {
+	if u, ok := UserFromContext(ctx); ok {
+		return HasPermission(u.Role, p)
+	}
+	if k, ok := APIKeyFromContext(ctx); ok {
+		have := make([]Permission, 0, len(k.Scopes))
+		for _, s := range k.Scopes {
+			have = append(have, Permission(s))
+		}
+		return grants(have, p)
+	}
+	return false
+}
+
+func grants(have []Permission, p Permission) bool {
+	for _, h := range have {
+		if h == p {
+			return true
+		}
+		for _, sub := range implied[h] {
+			if sub == p {
+				return true
+			}
+		}
+	}
+	return false
+}
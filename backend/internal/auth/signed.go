@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// signedValue is the envelope of SignValue: the payload and when it stops being valid.
+type signedValue struct {
+	Data json.RawMessage `json:"d"`
+	Exp  int64           `json:"e"`
+}
+
+// SignValue serializes v to JSON and signs it with HMAC-SHA256 for the given
+// purpose, so it can be handed to a client (e.g. in a cookie) and read back
+// with OpenValue until ttl has passed.
+func SignValue(secret []byte, purpose string, v any, ttl time.Duration) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(signedValue{Data: data, Exp: time.Now().Add(ttl).Unix()})
+	if err != nil {
+		return "", err
+	}
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(valueMAC(secret, purpose, payload)), nil
+}
+
+// OpenValue verifies a value produced by SignValue for the same purpose and decodes it into v.
+func OpenValue(secret []byte, purpose, signed string, v any) error {
+	payloadPart, sigPart, ok := strings.Cut(signed, ".")
+	if !ok {
+		return ErrInvalidToken
+	}
+	enc := base64.RawURLEncoding
+	payload, err := enc.DecodeString(payloadPart)
+	if err != nil {
+		return ErrInvalidToken
+	}
+	sig, err := enc.DecodeString(sigPart)
+	if err != nil || !hmac.Equal(sig, valueMAC(secret, purpose, payload)) {
+		return ErrInvalidToken
+	}
+	var sv signedValue
+	if err := json.Unmarshal(payload, &sv); err != nil || time.Now().Unix() >= sv.Exp {
+		return ErrInvalidToken
+	}
+	if err := json.Unmarshal(sv.Data, v); err != nil {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+func valueMAC(secret []byte, purpose string, payload []byte) []byte {
+	m := hmac.New(sha256.New, secret)
+	m.Write([]byte(purpose + ":"))
+	m.Write(payload)
+	return m.Sum(nil)
+}
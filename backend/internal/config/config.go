@@ -16,6 +16,24 @@ type Config struct {
 	CORSOrigins []string
 	AutoMigrate bool
 	AutoSeed    bool
+
+	// Session token lifetimes
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// TokenSecret signs participant access (magic-link) tokens and SSO login state
+	TokenSecret string
+
+	// OpenID Connect single sign-on; disabled unless OIDCIssuer is set
+	OIDCIssuer            string
+	OIDCClientID          string
+	OIDCClientSecret      string
+	OIDCRedirectURL       string
+	OIDCScopes            []string
+	OIDCGroupsClaim       string
+	OIDCRoleMap           map[string]string // IdP group -> local role
+	OIDCDefaultRole       string            // role of new users without a mapped group; "" refuses them
+	OIDCOrganizationID    string            // organization new SSO users are created in
+	OIDCPostLoginRedirect string            // frontend URL receiving the tokens; JSON response if empty
 }
 
 func Load() Config {
@@ -28,6 +46,24 @@ func Load() Config {
 		LogLevel:    strings.ToLower(getEnv("LOG_LEVEL", "info")),
 		AutoMigrate: getEnv("AUTO_MIGRATE", "true") == "true",
 		AutoSeed:    getEnv("AUTO_SEED", "true") == "true",
+
+		AccessTokenTTL:  getDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL: getDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+		TokenSecret:     getEnv("TOKEN_SECRET", ""),
+
+		OIDCIssuer:            getEnv("OIDC_ISSUER", ""),
+		OIDCClientID:          getEnv("OIDC_CLIENT_ID", ""),
+		OIDCClientSecret:      getEnv("OIDC_CLIENT_SECRET", ""),
+		OIDCRedirectURL:       getEnv("OIDC_REDIRECT_URL", ""),
+		OIDCScopes:            strings.Fields(getEnv("OIDC_SCOPES", "openid email profile")),
+		OIDCGroupsClaim:       getEnv("OIDC_GROUPS_CLAIM", "groups"),
+		OIDCRoleMap:           getMap("OIDC_ROLE_MAP"),
+		OIDCDefaultRole:       getEnv("OIDC_DEFAULT_ROLE", ""),
+		OIDCOrganizationID:    getEnv("OIDC_ORGANIZATION_ID", ""),
+		OIDCPostLoginRedirect: getEnv("OIDC_POST_LOGIN_REDIRECT", ""),
+	}
+	if cfg.TokenSecret == "" && cfg.Env == "dev" {
+		cfg.TokenSecret = "dev-insecure-token-secret"
 	}
 	cors := getEnv("CORS_ORIGINS", "")
 	if cors == "" {
@@ -70,3 +106,23 @@ func getEnv(key, def string) string {
 	}
 	return val
 }
+
+func getDuration(key string, def time.Duration) time.Duration {
+	d, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || d <= 0 {
+		return def
+	}
+	return d
+}
+
+// getMap parses "key=value,key=value".
+func getMap(key string) map[string]string {
+	out := map[string]string{}
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		if ok && strings.TrimSpace(k) != "" {
+			out[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_block_template_items_template_id;
+DROP TABLE IF EXISTS block_template_items;
+DROP TABLE IF EXISTS block_templates;
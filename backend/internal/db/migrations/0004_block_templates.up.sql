@@ -0,0 +1,25 @@
+-- Reusable block templates; schedule item times are stored relative to the block start
+CREATE TABLE IF NOT EXISTS block_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('activity','break')),
+    title TEXT NOT NULL,
+    description TEXT,
+    duration_minutes INTEGER CHECK (duration_minutes >= 0),
+    end_time_fixed BOOLEAN NOT NULL DEFAULT FALSE,
+    location_id UUID,
+    notes TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS block_template_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    template_id UUID NOT NULL,
+    offset_minutes INTEGER NOT NULL,
+    description TEXT NOT NULL,
+    staff_instructions TEXT,
+    guest_instructions TEXT,
+    notes TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_block_template_items_template_id ON block_template_items(template_id);
@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_block_templates_organization_id;
+DROP INDEX IF EXISTS idx_participants_organization_id;
+DROP INDEX IF EXISTS idx_vehicles_organization_id;
+DROP INDEX IF EXISTS idx_locations_organization_id;
+DROP INDEX IF EXISTS idx_events_organization_id;
+
+ALTER TABLE block_templates DROP COLUMN IF EXISTS organization_id;
+ALTER TABLE participants DROP COLUMN IF EXISTS organization_id;
+ALTER TABLE vehicles DROP COLUMN IF EXISTS organization_id;
+ALTER TABLE locations DROP COLUMN IF EXISTS organization_id;
+ALTER TABLE events DROP COLUMN IF EXISTS organization_id;
+
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,35 @@
+-- Organizations (tenants)
+CREATE TABLE IF NOT EXISTS organizations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Existing data is assigned to a default organization
+INSERT INTO organizations (name)
+SELECT 'Default Organization'
+WHERE NOT EXISTS (SELECT 1 FROM organizations);
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS organization_id UUID;
+ALTER TABLE locations ADD COLUMN IF NOT EXISTS organization_id UUID;
+ALTER TABLE vehicles ADD COLUMN IF NOT EXISTS organization_id UUID;
+ALTER TABLE participants ADD COLUMN IF NOT EXISTS organization_id UUID;
+ALTER TABLE block_templates ADD COLUMN IF NOT EXISTS organization_id UUID;
+
+UPDATE events SET organization_id = (SELECT id FROM organizations ORDER BY created_at LIMIT 1) WHERE organization_id IS NULL;
+UPDATE locations SET organization_id = (SELECT id FROM organizations ORDER BY created_at LIMIT 1) WHERE organization_id IS NULL;
+UPDATE vehicles SET organization_id = (SELECT id FROM organizations ORDER BY created_at LIMIT 1) WHERE organization_id IS NULL;
+UPDATE participants SET organization_id = (SELECT id FROM organizations ORDER BY created_at LIMIT 1) WHERE organization_id IS NULL;
+UPDATE block_templates SET organization_id = (SELECT id FROM organizations ORDER BY created_at LIMIT 1) WHERE organization_id IS NULL;
+
+ALTER TABLE events ALTER COLUMN organization_id SET NOT NULL;
+ALTER TABLE locations ALTER COLUMN organization_id SET NOT NULL;
+ALTER TABLE vehicles ALTER COLUMN organization_id SET NOT NULL;
+ALTER TABLE participants ALTER COLUMN organization_id SET NOT NULL;
+ALTER TABLE block_templates ALTER COLUMN organization_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_events_organization_id ON events(organization_id);
+CREATE INDEX IF NOT EXISTS idx_locations_organization_id ON locations(organization_id);
+CREATE INDEX IF NOT EXISTS idx_vehicles_organization_id ON vehicles(organization_id);
+CREATE INDEX IF NOT EXISTS idx_participants_organization_id ON participants(organization_id);
+CREATE INDEX IF NOT EXISTS idx_block_templates_organization_id ON block_templates(organization_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_events_organization_id_active;
+
+ALTER TABLE events
+DROP COLUMN IF EXISTS archived_at;
@@ -0,0 +1,5 @@
+-- Archived events are hidden from default listings and are read-only
+ALTER TABLE events
+ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_events_organization_id_active ON events(organization_id) WHERE archived_at IS NULL;
@@ -0,0 +1,3 @@
+-- IANA time zone the event's wall-clock times (blocks, movements, schedule items) are expressed in
+ALTER TABLE events
+ADD COLUMN IF NOT EXISTS time_zone TEXT NOT NULL DEFAULT 'UTC';
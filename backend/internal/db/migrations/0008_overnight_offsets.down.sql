@@ -0,0 +1,8 @@
+ALTER TABLE schedule_items
+DROP COLUMN IF EXISTS day_offset;
+
+ALTER TABLE movements
+DROP COLUMN IF EXISTS to_day_offset;
+
+ALTER TABLE blocks
+DROP COLUMN IF EXISTS end_day_offset;
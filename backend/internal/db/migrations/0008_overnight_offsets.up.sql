@@ -0,0 +1,21 @@
+-- Day offsets let blocks, movements and schedule items run past midnight:
+-- 0 = the day's own date, 1 = the following day, ...
+ALTER TABLE blocks
+ADD COLUMN IF NOT EXISTS end_day_offset INTEGER NOT NULL DEFAULT 0 CHECK (end_day_offset >= 0);
+
+ALTER TABLE movements
+ADD COLUMN IF NOT EXISTS to_day_offset INTEGER NOT NULL DEFAULT 0 CHECK (to_day_offset >= 0);
+
+ALTER TABLE schedule_items
+ADD COLUMN IF NOT EXISTS day_offset INTEGER NOT NULL DEFAULT 0 CHECK (day_offset >= 0);
+
+-- Existing rows whose end lies before their start were meant to end after midnight
+UPDATE blocks SET end_day_offset = 1
+WHERE end_time IS NOT NULL AND end_time < start_time;
+
+UPDATE movements SET to_day_offset = 1
+WHERE to_time_type = 'fixed' AND to_time IS NOT NULL AND to_time < from_time;
+
+UPDATE schedule_items si SET day_offset = 1
+FROM blocks b
+WHERE b.id = si.block_id AND b.end_day_offset > 0 AND si.time < b.start_time;
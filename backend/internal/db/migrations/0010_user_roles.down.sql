@@ -0,0 +1,3 @@
+UPDATE users SET role = 'participant' WHERE role IN ('planner','staff');
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('admin','participant'));
@@ -0,0 +1,4 @@
+-- Roles: admin manages everything, planner edits the schedule, staff reads everything,
+-- participant reads their own agenda
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('admin','planner','staff','participant'));
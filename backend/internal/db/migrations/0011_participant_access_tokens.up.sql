@@ -0,0 +1,14 @@
+-- Magic-link tokens giving read-only access to one participant's agenda.
+-- The token itself is HMAC-signed and never stored; rows allow listing and revocation.
+CREATE TABLE IF NOT EXISTS participant_access_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL,
+    participant_id UUID NOT NULL,
+    label TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_by UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_participant_access_tokens_participant ON participant_access_tokens(organization_id, participant_id);
@@ -0,0 +1,10 @@
+-- Per-event access for planner users: they only see events they are members of
+CREATE TABLE IF NOT EXISTS event_members (
+    event_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    role TEXT NOT NULL CHECK (role IN ('planner','viewer')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (event_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_members_user_id ON event_members(user_id);
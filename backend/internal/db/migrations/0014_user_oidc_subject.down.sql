@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_users_oidc_subject;
+ALTER TABLE users DROP COLUMN IF EXISTS oidc_subject;
@@ -0,0 +1,5 @@
+-- Users signing in through an OpenID Connect provider are linked by issuer and subject.
+-- SSO-only users have an empty password_hash, which never matches a password.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS oidc_subject TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_oidc_subject ON users(oidc_subject) WHERE oidc_subject IS NOT NULL;
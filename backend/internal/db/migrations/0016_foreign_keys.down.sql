@@ -0,0 +1,31 @@
+DROP INDEX IF EXISTS idx_users_participant_id;
+DROP INDEX IF EXISTS idx_vehicle_assignment_passengers_participant_id;
+
+ALTER TABLE api_keys DROP CONSTRAINT IF EXISTS fk_api_keys_created_by;
+ALTER TABLE participant_access_tokens DROP CONSTRAINT IF EXISTS fk_participant_access_tokens_created_by;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS fk_users_participant;
+ALTER TABLE vehicle_assignments DROP CONSTRAINT IF EXISTS fk_vehicle_assignments_driver;
+ALTER TABLE vehicle_assignments DROP CONSTRAINT IF EXISTS fk_vehicle_assignments_vehicle;
+ALTER TABLE block_templates DROP CONSTRAINT IF EXISTS fk_block_templates_location;
+ALTER TABLE vehicles DROP CONSTRAINT IF EXISTS fk_vehicles_origination_location;
+ALTER TABLE movements DROP CONSTRAINT IF EXISTS fk_movements_to_location;
+ALTER TABLE movements DROP CONSTRAINT IF EXISTS fk_movements_from_location;
+ALTER TABLE blocks DROP CONSTRAINT IF EXISTS fk_blocks_location;
+ALTER TABLE vehicle_assignment_passengers DROP CONSTRAINT IF EXISTS fk_vehicle_assignment_passengers_participant;
+ALTER TABLE block_met_by_participants DROP CONSTRAINT IF EXISTS fk_block_met_by_participants_participant;
+ALTER TABLE block_advance_participants DROP CONSTRAINT IF EXISTS fk_block_advance_participants_participant;
+ALTER TABLE block_participants DROP CONSTRAINT IF EXISTS fk_block_participants_participant;
+ALTER TABLE participant_access_tokens DROP CONSTRAINT IF EXISTS fk_participant_access_tokens_participant;
+ALTER TABLE sessions DROP CONSTRAINT IF EXISTS fk_sessions_user;
+ALTER TABLE event_members DROP CONSTRAINT IF EXISTS fk_event_members_user;
+ALTER TABLE event_members DROP CONSTRAINT IF EXISTS fk_event_members_event;
+ALTER TABLE block_template_items DROP CONSTRAINT IF EXISTS fk_block_template_items_template;
+ALTER TABLE vehicle_assignment_passengers DROP CONSTRAINT IF EXISTS fk_vehicle_assignment_passengers_assignment;
+ALTER TABLE vehicle_assignments DROP CONSTRAINT IF EXISTS fk_vehicle_assignments_movement;
+ALTER TABLE block_met_by_participants DROP CONSTRAINT IF EXISTS fk_block_met_by_participants_block;
+ALTER TABLE block_advance_participants DROP CONSTRAINT IF EXISTS fk_block_advance_participants_block;
+ALTER TABLE block_participants DROP CONSTRAINT IF EXISTS fk_block_participants_block;
+ALTER TABLE schedule_items DROP CONSTRAINT IF EXISTS fk_schedule_items_block;
+ALTER TABLE movements DROP CONSTRAINT IF EXISTS fk_movements_day;
+ALTER TABLE blocks DROP CONSTRAINT IF EXISTS fk_blocks_day;
+ALTER TABLE days DROP CONSTRAINT IF EXISTS fk_days_event;
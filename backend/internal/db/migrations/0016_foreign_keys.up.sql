@@ -0,0 +1,91 @@
+-- Referential integrity. A day owns its blocks and movements, which own their schedule
+-- items, participant lists and vehicle assignments: deleting the owner deletes them too.
+-- Removing a participant takes them off block lists and passenger lists. Locations,
+-- vehicles and drivers that are still in use cannot be deleted.
+-- organization_id columns are left as they are: organizations are never deleted.
+
+-- Remove rows left behind by earlier deletes
+DELETE FROM days d WHERE NOT EXISTS (SELECT 1 FROM events e WHERE e.id = d.event_id);
+DELETE FROM blocks b WHERE NOT EXISTS (SELECT 1 FROM days d WHERE d.id = b.day_id);
+DELETE FROM movements m WHERE NOT EXISTS (SELECT 1 FROM days d WHERE d.id = m.day_id);
+DELETE FROM schedule_items si WHERE NOT EXISTS (SELECT 1 FROM blocks b WHERE b.id = si.block_id);
+DELETE FROM block_participants bp
+WHERE NOT EXISTS (SELECT 1 FROM blocks b WHERE b.id = bp.block_id)
+   OR NOT EXISTS (SELECT 1 FROM participants p WHERE p.id = bp.participant_id);
+DELETE FROM block_advance_participants bp
+WHERE NOT EXISTS (SELECT 1 FROM blocks b WHERE b.id = bp.block_id)
+   OR NOT EXISTS (SELECT 1 FROM participants p WHERE p.id = bp.participant_id);
+DELETE FROM block_met_by_participants bp
+WHERE NOT EXISTS (SELECT 1 FROM blocks b WHERE b.id = bp.block_id)
+   OR NOT EXISTS (SELECT 1 FROM participants p WHERE p.id = bp.participant_id);
+DELETE FROM vehicle_assignments va
+WHERE NOT EXISTS (SELECT 1 FROM movements m WHERE m.id = va.movement_id)
+   OR NOT EXISTS (SELECT 1 FROM vehicles v WHERE v.id = va.vehicle_id);
+DELETE FROM vehicle_assignment_passengers vp
+WHERE NOT EXISTS (SELECT 1 FROM vehicle_assignments va WHERE va.id = vp.assignment_id)
+   OR NOT EXISTS (SELECT 1 FROM participants p WHERE p.id = vp.participant_id);
+DELETE FROM block_template_items ti WHERE NOT EXISTS (SELECT 1 FROM block_templates t WHERE t.id = ti.template_id);
+DELETE FROM event_members em
+WHERE NOT EXISTS (SELECT 1 FROM events e WHERE e.id = em.event_id)
+   OR NOT EXISTS (SELECT 1 FROM users u WHERE u.id = em.user_id);
+DELETE FROM sessions s WHERE NOT EXISTS (SELECT 1 FROM users u WHERE u.id = s.user_id);
+DELETE FROM participant_access_tokens t WHERE NOT EXISTS (SELECT 1 FROM participants p WHERE p.id = t.participant_id);
+
+-- Clear optional references to rows that no longer exist
+UPDATE blocks SET location_id = NULL
+WHERE location_id IS NOT NULL AND NOT EXISTS (SELECT 1 FROM locations l WHERE l.id = blocks.location_id);
+UPDATE movements SET from_location_id = NULL
+WHERE from_location_id IS NOT NULL AND NOT EXISTS (SELECT 1 FROM locations l WHERE l.id = movements.from_location_id);
+UPDATE movements SET to_location_id = NULL
+WHERE to_location_id IS NOT NULL AND NOT EXISTS (SELECT 1 FROM locations l WHERE l.id = movements.to_location_id);
+UPDATE vehicles SET origination_location_id = NULL
+WHERE origination_location_id IS NOT NULL AND NOT EXISTS (SELECT 1 FROM locations l WHERE l.id = vehicles.origination_location_id);
+UPDATE block_templates SET location_id = NULL
+WHERE location_id IS NOT NULL AND NOT EXISTS (SELECT 1 FROM locations l WHERE l.id = block_templates.location_id);
+UPDATE vehicle_assignments SET driver_id = NULL
+WHERE driver_id IS NOT NULL AND NOT EXISTS (SELECT 1 FROM participants p WHERE p.id = vehicle_assignments.driver_id);
+UPDATE users SET participant_id = NULL
+WHERE participant_id IS NOT NULL AND NOT EXISTS (SELECT 1 FROM participants p WHERE p.id = users.participant_id);
+UPDATE participant_access_tokens SET created_by = NULL
+WHERE created_by IS NOT NULL AND NOT EXISTS (SELECT 1 FROM users u WHERE u.id = participant_access_tokens.created_by);
+UPDATE api_keys SET created_by = NULL
+WHERE created_by IS NOT NULL AND NOT EXISTS (SELECT 1 FROM users u WHERE u.id = api_keys.created_by);
+
+-- Ownership: cascade
+ALTER TABLE days ADD CONSTRAINT fk_days_event FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE CASCADE;
+ALTER TABLE blocks ADD CONSTRAINT fk_blocks_day FOREIGN KEY (day_id) REFERENCES days(id) ON DELETE CASCADE;
+ALTER TABLE movements ADD CONSTRAINT fk_movements_day FOREIGN KEY (day_id) REFERENCES days(id) ON DELETE CASCADE;
+ALTER TABLE schedule_items ADD CONSTRAINT fk_schedule_items_block FOREIGN KEY (block_id) REFERENCES blocks(id) ON DELETE CASCADE;
+ALTER TABLE block_participants ADD CONSTRAINT fk_block_participants_block FOREIGN KEY (block_id) REFERENCES blocks(id) ON DELETE CASCADE;
+ALTER TABLE block_advance_participants ADD CONSTRAINT fk_block_advance_participants_block FOREIGN KEY (block_id) REFERENCES blocks(id) ON DELETE CASCADE;
+ALTER TABLE block_met_by_participants ADD CONSTRAINT fk_block_met_by_participants_block FOREIGN KEY (block_id) REFERENCES blocks(id) ON DELETE CASCADE;
+ALTER TABLE vehicle_assignments ADD CONSTRAINT fk_vehicle_assignments_movement FOREIGN KEY (movement_id) REFERENCES movements(id) ON DELETE CASCADE;
+ALTER TABLE vehicle_assignment_passengers ADD CONSTRAINT fk_vehicle_assignment_passengers_assignment FOREIGN KEY (assignment_id) REFERENCES vehicle_assignments(id) ON DELETE CASCADE;
+ALTER TABLE block_template_items ADD CONSTRAINT fk_block_template_items_template FOREIGN KEY (template_id) REFERENCES block_templates(id) ON DELETE CASCADE;
+ALTER TABLE event_members ADD CONSTRAINT fk_event_members_event FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE CASCADE;
+ALTER TABLE event_members ADD CONSTRAINT fk_event_members_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE sessions ADD CONSTRAINT fk_sessions_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE participant_access_tokens ADD CONSTRAINT fk_participant_access_tokens_participant FOREIGN KEY (participant_id) REFERENCES participants(id) ON DELETE CASCADE;
+
+-- Participants on lists: removing the participant removes the entry
+ALTER TABLE block_participants ADD CONSTRAINT fk_block_participants_participant FOREIGN KEY (participant_id) REFERENCES participants(id) ON DELETE CASCADE;
+ALTER TABLE block_advance_participants ADD CONSTRAINT fk_block_advance_participants_participant FOREIGN KEY (participant_id) REFERENCES participants(id) ON DELETE CASCADE;
+ALTER TABLE block_met_by_participants ADD CONSTRAINT fk_block_met_by_participants_participant FOREIGN KEY (participant_id) REFERENCES participants(id) ON DELETE CASCADE;
+ALTER TABLE vehicle_assignment_passengers ADD CONSTRAINT fk_vehicle_assignment_passengers_participant FOREIGN KEY (participant_id) REFERENCES participants(id) ON DELETE CASCADE;
+
+-- Shared resources in use: restrict
+ALTER TABLE blocks ADD CONSTRAINT fk_blocks_location FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE RESTRICT;
+ALTER TABLE movements ADD CONSTRAINT fk_movements_from_location FOREIGN KEY (from_location_id) REFERENCES locations(id) ON DELETE RESTRICT;
+ALTER TABLE movements ADD CONSTRAINT fk_movements_to_location FOREIGN KEY (to_location_id) REFERENCES locations(id) ON DELETE RESTRICT;
+ALTER TABLE vehicles ADD CONSTRAINT fk_vehicles_origination_location FOREIGN KEY (origination_location_id) REFERENCES locations(id) ON DELETE RESTRICT;
+ALTER TABLE block_templates ADD CONSTRAINT fk_block_templates_location FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE RESTRICT;
+ALTER TABLE vehicle_assignments ADD CONSTRAINT fk_vehicle_assignments_vehicle FOREIGN KEY (vehicle_id) REFERENCES vehicles(id) ON DELETE RESTRICT;
+ALTER TABLE vehicle_assignments ADD CONSTRAINT fk_vehicle_assignments_driver FOREIGN KEY (driver_id) REFERENCES participants(id) ON DELETE RESTRICT;
+ALTER TABLE users ADD CONSTRAINT fk_users_participant FOREIGN KEY (participant_id) REFERENCES participants(id) ON DELETE RESTRICT;
+
+-- Creators: keep the row when the user is deleted
+ALTER TABLE participant_access_tokens ADD CONSTRAINT fk_participant_access_tokens_created_by FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE api_keys ADD CONSTRAINT fk_api_keys_created_by FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_vehicle_assignment_passengers_participant_id ON vehicle_assignment_passengers(participant_id);
+CREATE INDEX IF NOT EXISTS idx_users_participant_id ON users(participant_id);
@@ -0,0 +1,5 @@
+ALTER TABLE locations DROP COLUMN IF EXISTS version;
+ALTER TABLE vehicles DROP COLUMN IF EXISTS version;
+ALTER TABLE participants DROP COLUMN IF EXISTS version;
+ALTER TABLE movements DROP COLUMN IF EXISTS version;
+ALTER TABLE blocks DROP COLUMN IF EXISTS version;
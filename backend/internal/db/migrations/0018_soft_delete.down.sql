@@ -0,0 +1,17 @@
+-- Deleted days are purged so that the unique (event_id, date) constraint can be restored;
+-- every other row still in the trash comes back
+DELETE FROM days WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_days_deleted_at;
+DROP INDEX IF EXISTS idx_movements_deleted_at;
+DROP INDEX IF EXISTS idx_blocks_deleted_at;
+
+DROP INDEX IF EXISTS uq_days_event_date;
+ALTER TABLE days ADD CONSTRAINT unique_event_date UNIQUE (event_id, date);
+
+ALTER TABLE vehicles DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE locations DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE participants DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE days DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE movements DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE blocks DROP COLUMN IF EXISTS deleted_at;
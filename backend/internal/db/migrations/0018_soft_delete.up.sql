@@ -0,0 +1,18 @@
+-- Soft delete: deleting a block, movement, day, participant, location or vehicle sets
+-- deleted_at and leaves the row and its child rows in place, so that it can be restored
+-- from the trash. Purging the row removes it for good.
+ALTER TABLE blocks ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE movements ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE days ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE participants ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE locations ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE vehicles ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- A deleted day must not block a new day on the same date
+ALTER TABLE days DROP CONSTRAINT IF EXISTS unique_event_date;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_days_event_date ON days(event_id, date) WHERE deleted_at IS NULL;
+
+-- Trash listing
+CREATE INDEX IF NOT EXISTS idx_blocks_deleted_at ON blocks(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_movements_deleted_at ON movements(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_days_deleted_at ON days(deleted_at) WHERE deleted_at IS NOT NULL;
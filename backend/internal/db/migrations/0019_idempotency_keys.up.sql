@@ -0,0 +1,15 @@
+-- Idempotency keys: the first response to a create sent with an Idempotency-Key header
+-- is stored, and retries with the same key replay it instead of creating again
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    organization_id UUID NOT NULL,
+    actor TEXT NOT NULL, -- "user:<id>" or "api_key:<id>"
+    key TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    status_code INTEGER, -- NULL while the request is being processed
+    content_type TEXT,
+    etag TEXT,
+    response_body BYTEA,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (organization_id, actor, key)
+);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys(created_at);
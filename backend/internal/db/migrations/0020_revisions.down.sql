@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS movement_revisions;
+DROP TABLE IF EXISTS block_revisions;
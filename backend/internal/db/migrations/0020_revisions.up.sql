@@ -0,0 +1,18 @@
+-- Revisions of blocks and movements: a JSON snapshot of the row with its schedule items,
+-- participants or vehicle assignments at each version, so that changes can be compared
+-- and reverted. Revisions go with the row when it is purged.
+CREATE TABLE IF NOT EXISTS block_revisions (
+    block_id UUID NOT NULL REFERENCES blocks(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    snapshot JSONB NOT NULL,
+    created_at TIMESTAMPTZ, -- NULL if recorded only when the block was next changed
+    PRIMARY KEY (block_id, version)
+);
+
+CREATE TABLE IF NOT EXISTS movement_revisions (
+    movement_id UUID NOT NULL REFERENCES movements(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    snapshot JSONB NOT NULL,
+    created_at TIMESTAMPTZ, -- NULL if recorded only when the movement was next changed
+    PRIMARY KEY (movement_id, version)
+);
@@ -0,0 +1,2 @@
+-- Users are always created with an explicit role; a missing role must fail instead of granting admin
+ALTER TABLE users ALTER COLUMN role DROP DEFAULT;
@@ -4,6 +4,8 @@ import (
 	"context"
 	"time"
 
+	"planning-system/backend/internal/auth"
+
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rs/zerolog"
@@ -17,7 +19,7 @@ func Run(ctx context.Context, pool *pgxpool.Pool, logger zerolog.Logger) error {
 	}
 	if count > 0 {
 		logger.Info().Msg("seed: data already exists, skipping")
-		return nil
+		return seedUsers(ctx, pool, logger)
 	}
 
 	logger.Info().Msg("seed: inserting demo data")
@@ -27,14 +29,23 @@ func Run(ctx context.Context, pool *pgxpool.Pool, logger zerolog.Logger) error {
 	}
 	defer tx.Rollback(ctx)
 
+	// Organization: reuse the default one created by the migrations
+	var orgID uuid.UUID
+	if err := tx.QueryRow(ctx, `SELECT id FROM organizations ORDER BY created_at ASC LIMIT 1`).Scan(&orgID); err != nil {
+		orgID = uuid.New()
+		if _, err := tx.Exec(ctx, `INSERT INTO organizations (id, name) VALUES ($1,$2)`, orgID, "Default Organization"); err != nil {
+			return err
+		}
+	}
+
 	// Event
 	eventID := uuid.New()
 	start := time.Now().UTC().Truncate(24 * time.Hour)
 	end := start.AddDate(0, 0, 2)
 	_, err = tx.Exec(ctx, `
-		INSERT INTO events (id, name, description, start_date, end_date)
-		VALUES ($1,$2,$3,$4,$5)
-	`, eventID, "Default Event", "Demo seeded event", start, end)
+		INSERT INTO events (id, organization_id, name, description, start_date, end_date)
+		VALUES ($1,$6,$2,$3,$4,$5)
+	`, eventID, "Default Event", "Demo seeded event", start, end, orgID)
 	if err != nil {
 		return err
 	}
@@ -43,11 +54,11 @@ func Run(ctx context.Context, pool *pgxpool.Pool, logger zerolog.Logger) error {
 	var locUni = uuid.New()
 	var locAirport = uuid.New()
 	_, err = tx.Exec(ctx, `
-		INSERT INTO locations (id, name, address, google_maps_link, type)
+		INSERT INTO locations (id, organization_id, name, address, google_maps_link, type)
 		VALUES
-		($1,'İstanbul Üniversitesi','Karaağaç, İstanbul Üniversitesi Merkez Kampüsü, 34500 Beyazıt/Büyükçekmece/İstanbul, Turquía','https://maps.app.goo.gl/HrZ5GAfDcykZDQU19','campus'),
-		($2,'Aeropuerto de Estambul','Tayakadın, Terminal Caddesi No:1, 34283 Arnavutköy/İstanbul, Turquía','https://maps.app.goo.gl/Fpeygpd1w1uusbXp6','airport')
-	`, locUni, locAirport)
+		($1,$3,'İstanbul Üniversitesi','Karaağaç, İstanbul Üniversitesi Merkez Kampüsü, 34500 Beyazıt/Büyükçekmece/İstanbul, Turquía','https://maps.app.goo.gl/HrZ5GAfDcykZDQU19','campus'),
+		($2,$3,'Aeropuerto de Estambul','Tayakadın, Terminal Caddesi No:1, 34283 Arnavutköy/İstanbul, Turquía','https://maps.app.goo.gl/Fpeygpd1w1uusbXp6','airport')
+	`, locUni, locAirport, orgID)
 	if err != nil {
 		return err
 	}
@@ -57,12 +68,12 @@ func Run(ctx context.Context, pool *pgxpool.Pool, logger zerolog.Logger) error {
 	bob := uuid.New()
 	carol := uuid.New()
 	_, err = tx.Exec(ctx, `
-		INSERT INTO participants (id, name, roles, email, phone, languages)
+		INSERT INTO participants (id, organization_id, name, roles, email, phone, languages)
 		VALUES
-		($1,'Alice Johnson', ARRAY['VIP'], 'alice@example.com', '+1 555-0100', ARRAY['English','French']),
-		($2,'Bob Lee', ARRAY['press'], 'bob@example.com', '+1 555-0101', ARRAY['English','Spanish','Chinese (Mandarin)']),
-		($3,'Carol Smith', ARRAY['staff'], 'carol@example.com', '+90 555-0102', ARRAY['English','Turkish'])
-	`, alice, bob, carol)
+		($1,$4,'Alice Johnson', ARRAY['VIP'], 'alice@example.com', '+1 555-0100', ARRAY['English','French']),
+		($2,$4,'Bob Lee', ARRAY['press'], 'bob@example.com', '+1 555-0101', ARRAY['English','Spanish','Chinese (Mandarin)']),
+		($3,$4,'Carol Smith', ARRAY['staff'], 'carol@example.com', '+90 555-0102', ARRAY['English','Turkish'])
+	`, alice, bob, carol, orgID)
 	if err != nil {
 		return err
 	}
@@ -71,11 +82,11 @@ func Run(ctx context.Context, pool *pgxpool.Pool, logger zerolog.Logger) error {
 	van := uuid.New()
 	sedan := uuid.New()
 	_, err = tx.Exec(ctx, `
-		INSERT INTO vehicles (id, label, make, model, license_plate, capacity, notes)
+		INSERT INTO vehicles (id, organization_id, label, make, model, license_plate, capacity, notes)
 		VALUES
-		($1,'Shuttle Van','Mercedes','Vito','34 ABC 123', 8, 'Spacious van'),
-		($2,'VIP Sedan','BMW','5 Series','34 VIP 001', 3, 'Comfort for VIP')
-	`, van, sedan)
+		($1,$3,'Shuttle Van','Mercedes','Vito','34 ABC 123', 8, 'Spacious van'),
+		($2,$3,'VIP Sedan','BMW','5 Series','34 VIP 001', 3, 'Comfort for VIP')
+	`, van, sedan, orgID)
 	if err != nil {
 		return err
 	}
@@ -176,6 +187,60 @@ func Run(ctx context.Context, pool *pgxpool.Pool, logger zerolog.Logger) error {
 		return err
 	}
 	logger.Info().Msg("seed: demo data inserted")
+	return seedUsers(ctx, pool, logger)
+}
+
+// seedEventRoles is the event membership given to seeded users of each role.
+var seedEventRoles = map[string]string{auth.RolePlanner: auth.EventRolePlanner}
+
+// seedUsers creates the dev login accounts if they are missing:
+// admin@example.com / admin, planner@example.com / planner, staff@example.com / staff,
+// and alice@example.com / alice linked to the participant Alice.
+func seedUsers(ctx context.Context, pool *pgxpool.Pool, logger zerolog.Logger) error {
+	var orgID string
+	if err := pool.QueryRow(ctx, `SELECT id::text FROM organizations ORDER BY created_at ASC LIMIT 1`).Scan(&orgID); err != nil {
+		return err
+	}
+	var alice *string
+	_ = pool.QueryRow(ctx, `SELECT id::text FROM participants WHERE organization_id=$1 AND email='alice@example.com' LIMIT 1`, orgID).Scan(&alice)
+	users := []struct {
+		email, name, password, role string
+		participantID               *string
+	}{
+		{"admin@example.com", "Admin", "admin", "admin", nil},
+		{"planner@example.com", "Planner", "planner", "planner", nil},
+		{"staff@example.com", "Staff", "staff", "staff", nil},
+		{"alice@example.com", "Alice Johnson", "alice", "participant", alice},
+	}
+	for _, u := range users {
+		hash, err := auth.HashPassword(u.password)
+		if err != nil {
+			return err
+		}
+		tag, err := pool.Exec(ctx, `
+			INSERT INTO users (organization_id, email, name, password_hash, role, participant_id)
+			SELECT $1::uuid, $2::text, $3::text, $4::text, $5::text, $6::uuid
+			WHERE NOT EXISTS (SELECT 1 FROM users WHERE lower(email) = lower($2))
+		`, orgID, u.email, u.name, hash, u.role, u.participantID)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() > 0 {
+			logger.Info().Str("email", u.email).Msg("seed: user created")
+		}
+		// New planner accounts get access to the existing events
+		if eventRole := seedEventRoles[u.role]; eventRole != "" && tag.RowsAffected() > 0 {
+			if _, err := pool.Exec(ctx, `
+				INSERT INTO event_members (event_id, user_id, role)
+				SELECT e.id, u.id, $3::text
+				FROM events e, users u
+				WHERE e.organization_id = $1::uuid AND lower(u.email) = lower($2::text)
+				ON CONFLICT DO NOTHING
+			`, orgID, u.email, eventRole); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"planning-system/backend/internal/auth"
+	"planning-system/backend/internal/models"
+	"planning-system/backend/internal/repos"
+	"planning-system/backend/internal/services"
+	"planning-system/backend/pkg/respond"
+)
+
+func (h *Handlers) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	items, err := h.sv.APIKeys.List(r.Context(), orgID(r))
+	if err != nil {
+		h.log.Error().Err(err).Msg("list api keys failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to list api keys")
+		return
+	}
+	respond.List(w, http.StatusOK, items, nil)
+}
+
+func (h *Handlers) GetAPIKey(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "keyId")
+	item, err := h.sv.APIKeys.Get(r.Context(), orgID(r), id)
+	if err != nil {
+		respond.Error(w, http.StatusNotFound, "api key not found")
+		return
+	}
+	respond.Single(w, http.StatusOK, item)
+}
+
+// CreateAPIKey issues a key. Body: { name, scopes: [...], expiresAt? }.
+// The key is only returned in this response.
+func (h *Handlers) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var in models.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		respond.Error(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	if err := services.ValidateAPIKey(&in); err != nil {
+		h.rejectInvalid(w, err)
+		return
+	}
+	createdBy := ""
+	if u, ok := auth.UserFromContext(r.Context()); ok {
+		createdBy = u.ID
+	}
+	item, err := h.sv.CreateAPIKey(r.Context(), orgID(r), createdBy, in)
+	if err != nil {
+		h.log.Error().Err(err).Msg("create api key failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to create api key")
+		return
+	}
+	respond.Single(w, http.StatusCreated, item)
+}
+
+// RevokeAPIKey stops the key from authenticating; it stays listed as revoked.
+func (h *Handlers) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "keyId")
+	if _, err := h.sv.APIKeys.Revoke(r.Context(), orgID(r), id); err != nil {
+		if err == repos.ErrNotFound {
+			respond.Error(w, http.StatusNotFound, "api key not found")
+			return
+		}
+		h.log.Error().Err(err).Str("key_id", id).Msg("revoke api key failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to revoke api key")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
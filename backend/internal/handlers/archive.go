@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"planning-system/backend/internal/models"
+	"planning-system/backend/internal/repos"
+	"planning-system/backend/pkg/respond"
+)
+
+func (h *Handlers) ArchiveEvent(w http.ResponseWriter, r *http.Request) {
+	h.setEventArchived(w, r, true)
+}
+
+func (h *Handlers) UnarchiveEvent(w http.ResponseWriter, r *http.Request) {
+	h.setEventArchived(w, r, false)
+}
+
+func (h *Handlers) setEventArchived(w http.ResponseWriter, r *http.Request, archived bool) {
+	id := chi.URLParam(r, "eventId")
+	item, err := h.sv.Events.SetArchived(r.Context(), orgID(r), id, archived)
+	if err != nil {
+		if err == repos.ErrNotFound {
+			respond.Error(w, http.StatusNotFound, "event not found")
+			return
+		}
+		h.log.Error().Err(err).Str("event_id", id).Bool("archived", archived).Msg("set event archived failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to update event")
+		return
+	}
+	respond.Single(w, http.StatusOK, item)
+}
+
+// includeArchived reports whether the caller asked for archived events with ?includeArchived=true.
+func includeArchived(r *http.Request) bool {
+	return r.URL.Query().Get("includeArchived") == "true"
+}
+
+// readableEvent loads the event for read endpoints. Archived events are hidden
+// unless ?includeArchived=true, and events the caller is not a member of are hidden.
+// It writes the error response and returns false on failure.
+func (h *Handlers) readableEvent(w http.ResponseWriter, r *http.Request, eventID string) (models.Event, bool) {
+	ev, err := h.sv.Events.Get(r.Context(), orgID(r), eventID)
+	if err != nil || (ev.ArchivedAt != nil && !includeArchived(r)) {
+		respond.Error(w, http.StatusNotFound, "event not found")
+		return models.Event{}, false
+	}
+	if !h.checkEventAccess(w, r, ev.ID, "event not found", false) {
+		return models.Event{}, false
+	}
+	return ev, true
+}
+
+// readableDay checks that the day exists and that the caller can see its event.
+func (h *Handlers) readableDay(w http.ResponseWriter, r *http.Request, dayID string) bool {
+	ev, err := h.sv.Events.GetByDay(r.Context(), orgID(r), dayID)
+	if err != nil {
+		respond.Error(w, http.StatusNotFound, "day not found")
+		return false
+	}
+	return h.checkEventAccess(w, r, ev.ID, "day not found", false)
+}
+
+// writableEvent rejects changes to an archived event with 409, and changes by
+// callers without planner access to the event with 404 or 403.
+// It writes the error response and returns false on failure.
+func (h *Handlers) writableEvent(w http.ResponseWriter, r *http.Request, eventID string) bool {
+	ev, err := h.sv.Events.Get(r.Context(), orgID(r), eventID)
+	if err != nil {
+		if err == repos.ErrNotFound {
+			respond.Error(w, http.StatusNotFound, "event not found")
+			return false
+		}
+		respond.Error(w, http.StatusInternalServerError, "failed to load event")
+		return false
+	}
+	if !h.checkEventAccess(w, r, ev.ID, "event not found", true) {
+		return false
+	}
+	return checkNotArchived(w, ev)
+}
+
+// writableDay is writableEvent for the event owning the day.
+func (h *Handlers) writableDay(w http.ResponseWriter, r *http.Request, dayID string) bool {
+	ev, err := h.sv.Events.GetByDay(r.Context(), orgID(r), dayID)
+	if err != nil {
+		if err == repos.ErrNotFound {
+			respond.Error(w, http.StatusNotFound, "day not found")
+			return false
+		}
+		respond.Error(w, http.StatusInternalServerError, "failed to load event")
+		return false
+	}
+	if !h.checkEventAccess(w, r, ev.ID, "day not found", true) {
+		return false
+	}
+	return checkNotArchived(w, ev)
+}
+
+func checkNotArchived(w http.ResponseWriter, ev models.Event) bool {
+	if ev.ArchivedAt != nil {
+		respond.Error(w, http.StatusConflict, "event is archived; unarchive it to make changes")
+		return false
+	}
+	return true
+}
+
+// writableBlock checks that the block belongs to the day and that the day's event is not archived.
+// It returns the block as currently stored.
+func (h *Handlers) writableBlock(w http.ResponseWriter, r *http.Request, dayID, id string) (models.Block, bool) {
+	b, err := h.sv.Blocks.Get(r.Context(), orgID(r), dayID, id)
+	if err != nil {
+		respond.Error(w, http.StatusNotFound, "block not found")
+		return models.Block{}, false
+	}
+	return b, h.writableDay(w, r, dayID)
+}
+
+// writableMovement checks that the movement belongs to the day and that the day's event is not archived.
+// It returns the movement as currently stored.
+func (h *Handlers) writableMovement(w http.ResponseWriter, r *http.Request, dayID, id string) (models.Movement, bool) {
+	m, err := h.sv.Movements.Get(r.Context(), orgID(r), dayID, id)
+	if err != nil {
+		respond.Error(w, http.StatusNotFound, "movement not found")
+		return models.Movement{}, false
+	}
+	return m, h.writableDay(w, r, dayID)
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"planning-system/backend/internal/auth"
+	"planning-system/backend/internal/models"
+	"planning-system/backend/internal/repos"
+	"planning-system/backend/pkg/respond"
+)
+
+// Audited entity types.
+const (
+	auditLocation    = "location"
+	auditVehicle     = "vehicle"
+	auditParticipant = "participant"
+	auditDay         = "day"
+	auditBlock       = "block"
+	auditMovement    = "movement"
+)
+
+// ListAudit lists audit entries, newest first.
+// Query: entityType, entityId, actorId, action, from, to (RFC 3339 or YYYY-MM-DD), limit, offset.
+func (h *Handlers) ListAudit(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	f := models.AuditFilter{
+		EntityType: q.Get("entityType"),
+		EntityID:   q.Get("entityId"),
+		ActorID:    q.Get("actorId"),
+		Action:     q.Get("action"),
+	}
+	for _, id := range []string{f.EntityID, f.ActorID} {
+		if id != "" && !validUUID(id) {
+			respond.Error(w, http.StatusBadRequest, "entityId and actorId must be UUIDs")
+			return
+		}
+	}
+	var ok bool
+	if f.From, ok = parseAuditTime(q.Get("from")); !ok {
+		respond.Error(w, http.StatusBadRequest, "from must be RFC 3339 or YYYY-MM-DD")
+		return
+	}
+	if f.To, ok = parseAuditTime(q.Get("to")); !ok {
+		respond.Error(w, http.StatusBadRequest, "to must be RFC 3339 or YYYY-MM-DD")
+		return
+	}
+	page := repos.ParsePagination(q.Get("limit"), q.Get("offset"))
+	items, total, err := h.sv.Audit.List(r.Context(), orgID(r), f, page)
+	if err != nil {
+		h.log.Error().Err(err).Msg("list audit failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to list audit entries")
+		return
+	}
+	respond.List(w, http.StatusOK, items, &total)
+}
+
+// audit records a change made through the API. Errors are logged rather than
+// returned: the change itself has already been committed.
+func (h *Handlers) audit(r *http.Request, action, entityType, entityID string, before, after any) {
+	e := models.AuditEntry{Action: action, EntityType: entityType, EntityID: entityID}
+	if u, ok := auth.UserFromContext(r.Context()); ok {
+		e.ActorType, e.ActorID, e.ActorName = "user", &u.ID, u.Email
+	} else if k, ok := auth.APIKeyFromContext(r.Context()); ok {
+		e.ActorType, e.ActorID, e.ActorName = "api_key", &k.ID, k.Name
+	} else {
+		return
+	}
+	var err error
+	if before != nil {
+		if e.Before, err = json.Marshal(before); err != nil {
+			h.log.Error().Err(err).Str("entity_id", entityID).Msg("audit snapshot failed")
+			return
+		}
+	}
+	if after != nil {
+		if e.After, err = json.Marshal(after); err != nil {
+			h.log.Error().Err(err).Str("entity_id", entityID).Msg("audit snapshot failed")
+			return
+		}
+	}
+	if err := h.sv.Audit.Record(r.Context(), orgID(r), e); err != nil {
+		h.log.Error().Err(err).Str("entity_type", entityType).Str("entity_id", entityID).Str("action", action).Msg("audit record failed")
+	}
+}
+
+func parseAuditTime(s string) (*time.Time, bool) {
+	if s == "" {
+		return nil, true
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return &t, true
+		}
+	}
+	return nil, false
+}
+
+func validUUID(s string) bool {
+	_, err := uuid.Parse(s)
+	return err == nil
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"planning-system/backend/internal/auth"
+	"planning-system/backend/internal/models"
+	"planning-system/backend/pkg/respond"
+)
+
+// Login exchanges email and password for an access/refresh token pair.
+// Body: { email, password }
+func (h *Handlers) Login(w http.ResponseWriter, r *http.Request) {
+	var in models.LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		respond.Error(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	if in.Email == "" || in.Password == "" {
+		respond.Error(w, http.StatusBadRequest, "email and password are required")
+		return
+	}
+	item, err := h.sv.Login(r.Context(), in.Email, in.Password)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidCredentials) {
+			respond.Error(w, http.StatusUnauthorized, "invalid email or password")
+			return
+		}
+		h.log.Error().Err(err).Msg("login failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to log in")
+		return
+	}
+	respond.Single(w, http.StatusOK, item)
+}
+
+// Refresh rotates the token pair. Body: { refreshToken }
+func (h *Handlers) Refresh(w http.ResponseWriter, r *http.Request) {
+	var in models.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		respond.Error(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	if in.RefreshToken == "" {
+		respond.Error(w, http.StatusBadRequest, "refreshToken is required")
+		return
+	}
+	item, err := h.sv.Refresh(r.Context(), in.RefreshToken)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidToken) {
+			respond.Error(w, http.StatusUnauthorized, "invalid or expired refresh token")
+			return
+		}
+		h.log.Error().Err(err).Msg("token refresh failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to refresh session")
+		return
+	}
+	respond.Single(w, http.StatusOK, item)
+}
+
+// Logout revokes the session of the presented access token.
+func (h *Handlers) Logout(w http.ResponseWriter, r *http.Request) {
+	if err := h.sv.Logout(r.Context(), auth.BearerToken(r)); err != nil {
+		respond.Error(w, http.StatusInternalServerError, "failed to log out")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Me returns the authenticated user, or the API key when called with one.
+func (h *Handlers) Me(w http.ResponseWriter, r *http.Request) {
+	if k, ok := auth.APIKeyFromContext(r.Context()); ok {
+		respond.Single(w, http.StatusOK, k)
+		return
+	}
+	u, _ := auth.UserFromContext(r.Context())
+	respond.Single(w, http.StatusOK, u)
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"planning-system/backend/internal/models"
+	"planning-system/backend/internal/repos"
+	"planning-system/backend/internal/services"
+	"planning-system/backend/pkg/respond"
+)
+
+func (h *Handlers) ListBlockTemplates(w http.ResponseWriter, r *http.Request) {
+	items, err := h.sv.BlockTemplates.List(r.Context(), orgID(r))
+	if err != nil {
+		respond.Error(w, http.StatusInternalServerError, "failed to list block templates")
+		return
+	}
+	respond.List(w, http.StatusOK, items, nil)
+}
+
+func (h *Handlers) GetBlockTemplate(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "templateId")
+	item, err := h.sv.BlockTemplates.Get(r.Context(), orgID(r), id)
+	if err != nil {
+		respond.Error(w, http.StatusNotFound, "block template not found")
+		return
+	}
+	respond.Single(w, http.StatusOK, item)
+}
+
+func (h *Handlers) CreateBlockTemplate(w http.ResponseWriter, r *http.Request) {
+	var in models.BlockTemplate
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		respond.Error(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	if err := h.sv.ValidateBlockTemplate(r.Context(), orgID(r), in); err != nil {
+		h.rejectInvalid(w, err)
+		return
+	}
+	item, err := h.sv.BlockTemplates.Create(r.Context(), orgID(r), in)
+	if err != nil {
+		if missingReference(w, err) {
+			return
+		}
+		h.log.Error().Err(err).Msg("create block template failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to create block template")
+		return
+	}
+	respond.Single(w, http.StatusCreated, item)
+}
+
+func (h *Handlers) UpdateBlockTemplate(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "templateId")
+	var in models.BlockTemplate
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		respond.Error(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	if err := h.sv.ValidateBlockTemplate(r.Context(), orgID(r), in); err != nil {
+		h.rejectInvalid(w, err)
+		return
+	}
+	item, err := h.sv.BlockTemplates.Update(r.Context(), orgID(r), id, in)
+	if err != nil {
+		if err == repos.ErrNotFound {
+			respond.Error(w, http.StatusNotFound, "block template not found")
+			return
+		}
+		if missingReference(w, err) {
+			return
+		}
+		h.log.Error().Err(err).Str("template_id", id).Msg("update block template failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to update block template")
+		return
+	}
+	respond.Single(w, http.StatusOK, item)
+}
+
+func (h *Handlers) DeleteBlockTemplate(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "templateId")
+	if err := h.sv.BlockTemplates.Delete(r.Context(), orgID(r), id); err != nil {
+		if err == repos.ErrNotFound {
+			respond.Error(w, http.StatusNotFound, "block template not found")
+			return
+		}
+		h.log.Error().Err(err).Str("template_id", id).Msg("delete block template failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to delete block template")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SaveBlockAsTemplate stores an existing block as a named template.
+// Body: { name }
+func (h *Handlers) SaveBlockAsTemplate(w http.ResponseWriter, r *http.Request) {
+	dayID := chi.URLParam(r, "dayId")
+	id := chi.URLParam(r, "blockId")
+	var in models.SaveBlockTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		respond.Error(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	if err := services.ValidateSaveBlockTemplate(in); err != nil {
+		h.rejectInvalid(w, err)
+		return
+	}
+	if !h.readableDay(w, r, dayID) {
+		return
+	}
+	item, err := h.sv.SaveBlockAsTemplate(r.Context(), orgID(r), dayID, id, in.Name)
+	if err != nil {
+		if err == repos.ErrNotFound {
+			respond.Error(w, http.StatusNotFound, "block not found")
+			return
+		}
+		h.log.Error().Err(err).Str("block_id", id).Msg("save block template failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to save block template")
+		return
+	}
+	respond.Single(w, http.StatusCreated, item)
+}
+
+// CreateBlockFromTemplate instantiates a template as a new block on the day.
+// Body: { templateId, startTime: "HH:mm" }
+func (h *Handlers) CreateBlockFromTemplate(w http.ResponseWriter, r *http.Request) {
+	dayID := chi.URLParam(r, "dayId")
+	var in models.InstantiateBlockTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		respond.Error(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	if err := services.ValidateInstantiateBlockTemplate(in); err != nil {
+		h.rejectInvalid(w, err)
+		return
+	}
+	if !h.writableDay(w, r, dayID) {
+		return
+	}
+	item, err := h.sv.InstantiateBlockTemplate(r.Context(), orgID(r), dayID, in)
+	if err != nil {
+		var verr *services.ValidationError
+		if err == repos.ErrNotFound {
+			respond.Error(w, http.StatusNotFound, "block template not found")
+			return
+		}
+		if errors.As(err, &verr) {
+			respond.Invalid(w, verr.Fields)
+			return
+		}
+		if missingReference(w, err) {
+			return
+		}
+		h.log.Error().Err(err).Str("template_id", in.TemplateID).Msg("instantiate block template failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to create block from template")
+		return
+	}
+	h.audit(r, "create", auditBlock, item.ID, nil, item)
+	respond.Single(w, http.StatusCreated, item)
+}
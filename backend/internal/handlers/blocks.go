@@ -5,29 +5,44 @@ import (
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	"planning-system/backend/internal/auth"
 	"planning-system/backend/internal/models"
 	"planning-system/backend/internal/repos"
+	"planning-system/backend/internal/services"
 	"planning-system/backend/pkg/respond"
 )
 
 func (h *Handlers) ListBlocks(w http.ResponseWriter, r *http.Request) {
 	dayID := chi.URLParam(r, "dayId")
-	items, err := h.sv.Blocks.ListByDay(r.Context(), dayID)
+	if !h.readableDay(w, r, dayID) {
+		return
+	}
+	items, err := h.sv.Blocks.ListByDay(r.Context(), orgID(r), dayID)
 	if err != nil {
 		respond.Error(w, http.StatusInternalServerError, "failed to list blocks")
 		return
 	}
+	if audience(r) == auth.AudienceGuest {
+		redactBlocks(items)
+	}
 	respond.List(w, http.StatusOK, items, nil)
 }
 
 func (h *Handlers) GetBlock(w http.ResponseWriter, r *http.Request) {
 	dayID := chi.URLParam(r, "dayId")
 	id := chi.URLParam(r, "blockId")
-	item, err := h.sv.Blocks.Get(r.Context(), dayID, id)
+	if !h.readableDay(w, r, dayID) {
+		return
+	}
+	item, err := h.sv.Blocks.Get(r.Context(), orgID(r), dayID, id)
 	if err != nil {
 		respond.Error(w, http.StatusNotFound, "block not found")
 		return
 	}
+	if audience(r) == auth.AudienceGuest {
+		redactBlock(&item)
+	}
+	setETag(w, item.Version)
 	respond.Single(w, http.StatusOK, item)
 }
 
@@ -38,50 +53,119 @@ func (h *Handlers) CreateBlock(w http.ResponseWriter, r *http.Request) {
 		respond.Error(w, http.StatusBadRequest, "invalid json")
 		return
 	}
-	in.DayID = dayID
-	if in.Title == "" || in.StartTime == "" || (in.Type != "activity" && in.Type != "break") {
-		respond.Error(w, http.StatusBadRequest, "invalid block payload")
+	in.ID, in.DayID = "", dayID
+	if !h.writableDay(w, r, dayID) {
+		return
+	}
+	if err := h.sv.ValidateBlock(r.Context(), orgID(r), &in); err != nil {
+		h.rejectInvalid(w, err)
 		return
 	}
-	item, err := h.sv.Blocks.Create(r.Context(), in)
+	item, err := h.sv.Blocks.Create(r.Context(), orgID(r), in)
 	if err != nil {
-		respond.Error(w, http.StatusBadRequest, "failed to create block")
+		if err == repos.ErrNotFound {
+			respond.Error(w, http.StatusNotFound, "day not found")
+			return
+		}
+		if missingReference(w, err) {
+			return
+		}
+		h.log.Error().Err(err).Str("day_id", dayID).Msg("create block failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to create block")
 		return
 	}
+	h.audit(r, "create", auditBlock, item.ID, nil, item)
+	setETag(w, item.Version)
 	respond.Single(w, http.StatusCreated, item)
 }
 
 func (h *Handlers) UpdateBlock(w http.ResponseWriter, r *http.Request) {
-	_ = chi.URLParam(r, "dayId") // dayId not needed; block id is global
+	dayID := chi.URLParam(r, "dayId")
 	id := chi.URLParam(r, "blockId")
 	var in models.Block
 	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
 		respond.Error(w, http.StatusBadRequest, "invalid json")
 		return
 	}
-	if in.Title == "" || in.StartTime == "" || (in.Type != "activity" && in.Type != "break") {
-		respond.Error(w, http.StatusBadRequest, "invalid block payload")
+	before, ok := h.writableBlock(w, r, dayID, id)
+	if !ok {
+		return
+	}
+	h.saveBlock(w, r, before, in, "update", repos.AllBlockRelations)
+}
+
+// PatchBlock applies a JSON merge patch (RFC 7396) to the block. Its participant lists
+// and schedule items are only rewritten if the patch contains them; a list in a patch
+// replaces the whole list.
+func (h *Handlers) PatchBlock(w http.ResponseWriter, r *http.Request) {
+	before, ok := h.writableBlock(w, r, chi.URLParam(r, "dayId"), chi.URLParam(r, "blockId"))
+	if !ok {
+		return
+	}
+	var in models.Block
+	patch, ok := mergePatch(w, r, before, &in)
+	if !ok {
+		return
+	}
+	h.saveBlock(w, r, before, in, "update", repos.BlockRelations{
+		Participants:        patch.has("participantsIds"),
+		AdvanceParticipants: patch.has("advanceParticipantIds"),
+		MetByParticipants:   patch.has("metByParticipantIds"),
+		ScheduleItems:       patch.has("scheduleItems"),
+	})
+}
+
+// saveBlock validates in and stores it over the block before, subject to If-Match,
+// replacing the lists selected by rel, and records the change in the audit log under action.
+func (h *Handlers) saveBlock(w http.ResponseWriter, r *http.Request, before, in models.Block, action string, rel repos.BlockRelations) {
+	in.ID = before.ID
+	if err := h.sv.ValidateBlock(r.Context(), orgID(r), &in); err != nil {
+		h.rejectInvalid(w, err)
+		return
+	}
+	var ok bool
+	if in.Version, ok = matchVersion(w, r, before, before.Version); !ok {
 		return
 	}
-	item, err := h.sv.Blocks.Update(r.Context(), id, in)
+	id := before.ID
+	item, err := h.sv.Blocks.Patch(r.Context(), orgID(r), id, in, rel)
 	if err != nil {
 		if err == repos.ErrNotFound {
 			respond.Error(w, http.StatusNotFound, "block not found")
 			return
 		}
-		respond.Error(w, http.StatusBadRequest, "failed to update block")
+		if err == repos.ErrVersionMismatch {
+			if current, err := h.sv.Blocks.Get(r.Context(), orgID(r), before.DayID, id); err == nil {
+				preconditionFailed(w, current, current.Version)
+				return
+			}
+		}
+		if err == repos.ErrForeignScheduleItem {
+			respond.Invalid(w, []respond.FieldError{{Field: "scheduleItems", Code: services.CodeNotFound, Message: "a schedule item was moved to another block meanwhile"}})
+			return
+		}
+		if missingReference(w, err) {
+			return
+		}
+		h.log.Error().Err(err).Str("block_id", id).Msg(action + " block failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to "+action+" block")
 		return
 	}
+	h.audit(r, action, auditBlock, id, before, item)
+	setETag(w, item.Version)
 	respond.Single(w, http.StatusOK, item)
 }
 
 func (h *Handlers) DeleteBlock(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "blockId")
-	if err := h.sv.Blocks.Delete(r.Context(), id); err != nil {
+	before, ok := h.writableBlock(w, r, chi.URLParam(r, "dayId"), id)
+	if !ok {
+		return
+	}
+	if err := h.sv.Blocks.Delete(r.Context(), orgID(r), id); err != nil {
 		respond.Error(w, http.StatusInternalServerError, "failed to delete block")
 		return
 	}
+	h.audit(r, "delete", auditBlock, id, before, nil)
 	w.WriteHeader(http.StatusNoContent)
 }
-
-
@@ -2,70 +2,135 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	"planning-system/backend/internal/auth"
 	"planning-system/backend/internal/models"
+	"planning-system/backend/internal/repos"
+	"planning-system/backend/internal/services"
 	"planning-system/backend/pkg/respond"
 )
 
 func (h *Handlers) ListDays(w http.ResponseWriter, r *http.Request) {
-	items, err := h.sv.Days.List(r.Context())
+	eventID := chi.URLParam(r, "eventId")
+	if _, ok := h.readableEvent(w, r, eventID); !ok {
+		return
+	}
+	items, err := h.sv.Days.List(r.Context(), orgID(r), eventID)
 	if err != nil {
 		respond.Error(w, http.StatusInternalServerError, "failed to list days")
 		return
 	}
+	if audience(r) == auth.AudienceGuest {
+		redactDays(items)
+	}
 	respond.List(w, http.StatusOK, items, nil)
 }
 
 func (h *Handlers) GetDay(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "dayId")
-	item, err := h.sv.Days.Get(r.Context(), id)
+	if !h.readableDay(w, r, id) {
+		return
+	}
+	item, err := h.sv.Days.Get(r.Context(), orgID(r), id)
 	if err != nil {
 		respond.Error(w, http.StatusNotFound, "day not found")
 		return
 	}
+	if audience(r) == auth.AudienceGuest {
+		redactDay(&item)
+	}
 	respond.Single(w, http.StatusOK, item)
 }
 
-// CreateDays accepts an array of dates to create.
+// CreateDays accepts an array of dates to create for the event in the URL.
 // Body: { dates: ["YYYY-MM-DD", "YYYY-MM-DD", ...] }
 func (h *Handlers) CreateDays(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "eventId")
+	if !h.writableEvent(w, r, eventID) {
+		return
+	}
 	var in models.CreateDaysRequest
 	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
 		respond.Error(w, http.StatusBadRequest, "invalid json")
 		return
 	}
-	if len(in.Dates) == 0 {
-		respond.Error(w, http.StatusBadRequest, "dates array cannot be empty")
-		return
-	}
-	// Find event_id (single-event model): pick first event
-	var eventID string
-	if err := h.sv.Days.Pool.QueryRow(r.Context(), `SELECT id FROM events ORDER BY start_date ASC LIMIT 1`).Scan(&eventID); err != nil || eventID == "" {
-		respond.Error(w, http.StatusBadRequest, "no event found to attach days")
+	if err := services.ValidateCreateDays(in); err != nil {
+		h.rejectInvalid(w, err)
 		return
 	}
 	// Create days for each date in the array
 	var allCreated []models.Day
 	for _, dateStr := range in.Dates {
-		items, err := h.sv.Days.CreateRange(r.Context(), eventID, dateStr, dateStr)
+		items, err := h.sv.Days.CreateRange(r.Context(), orgID(r), eventID, dateStr, dateStr)
 		if err != nil {
-			respond.Error(w, http.StatusBadRequest, "failed to create days: invalid date format")
+			h.log.Error().Err(err).Str("event_id", eventID).Msg("create days failed")
+			respond.Error(w, http.StatusInternalServerError, "failed to create days")
 			return
 		}
 		allCreated = append(allCreated, items...)
 	}
+	for _, d := range allCreated {
+		h.audit(r, "create", auditDay, d.ID, nil, d)
+	}
 	respond.List(w, http.StatusCreated, allCreated, nil)
 }
 
 func (h *Handlers) DeleteDay(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "dayId")
-	if err := h.sv.Days.Delete(r.Context(), id); err != nil {
+	if !h.writableDay(w, r, id) {
+		return
+	}
+	before, getErr := h.sv.Days.Get(r.Context(), orgID(r), id)
+	if err := h.sv.Days.Delete(r.Context(), orgID(r), id); err != nil {
 		respond.Error(w, http.StatusInternalServerError, "failed to delete day")
 		return
 	}
+	if getErr == nil {
+		h.audit(r, "delete", auditDay, id, before, nil)
+	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
-
+// DuplicateDay copies a day's blocks and movements onto another day.
+// Body: { targetDayId? | date?, mode?: "merge"|"replace", dropParticipants?, dropVehicleAssignments? }
+func (h *Handlers) DuplicateDay(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "dayId")
+	var in models.DuplicateDayRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		respond.Error(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	if err := services.ValidateDuplicateDay(&in); err != nil {
+		h.rejectInvalid(w, err)
+		return
+	}
+	// The target day lives in the source day's event unless an explicit target is given.
+	target := id
+	if in.TargetDayID != "" {
+		target = in.TargetDayID
+	}
+	if target != id && !h.readableDay(w, r, id) {
+		return
+	}
+	if !h.writableDay(w, r, target) {
+		return
+	}
+	item, err := h.sv.Days.Duplicate(r.Context(), orgID(r), id, in)
+	if err != nil {
+		switch {
+		case errors.Is(err, repos.ErrNotFound):
+			respond.Error(w, http.StatusNotFound, "day not found")
+		case errors.Is(err, repos.ErrSameDay):
+			respond.Error(w, http.StatusBadRequest, "cannot duplicate a day onto itself")
+		default:
+			h.log.Error().Err(err).Str("day_id", id).Msg("duplicate day failed")
+			respond.Error(w, http.StatusInternalServerError, "failed to duplicate day")
+		}
+		return
+	}
+	h.audit(r, "duplicate", auditDay, item.Day.ID, nil, map[string]any{"sourceDayId": id, "request": in, "result": item})
+	respond.Single(w, http.StatusCreated, item)
+}
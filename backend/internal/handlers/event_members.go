@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"planning-system/backend/internal/auth"
+	"planning-system/backend/internal/models"
+	"planning-system/backend/internal/repos"
+	"planning-system/backend/internal/services"
+	"planning-system/backend/pkg/respond"
+)
+
+func (h *Handlers) ListEventMembers(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "eventId")
+	if _, err := h.sv.Events.Get(r.Context(), orgID(r), eventID); err != nil {
+		respond.Error(w, http.StatusNotFound, "event not found")
+		return
+	}
+	items, err := h.sv.EventMembers.List(r.Context(), orgID(r), eventID)
+	if err != nil {
+		h.log.Error().Err(err).Str("event_id", eventID).Msg("list event members failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to list event members")
+		return
+	}
+	respond.List(w, http.StatusOK, items, nil)
+}
+
+// PutEventMember grants a user access to the event or changes their role.
+// Body: { role: "planner"|"viewer" }
+func (h *Handlers) PutEventMember(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "eventId")
+	userID := chi.URLParam(r, "userId")
+	var in models.PutEventMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		respond.Error(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	if err := services.ValidateEventMember(in); err != nil {
+		h.rejectInvalid(w, err)
+		return
+	}
+	if _, err := h.sv.Events.Get(r.Context(), orgID(r), eventID); err != nil {
+		respond.Error(w, http.StatusNotFound, "event not found")
+		return
+	}
+	u, err := h.sv.Users.Get(r.Context(), userID)
+	if err != nil || u.OrganizationID != orgID(r) {
+		respond.Error(w, http.StatusNotFound, "user not found")
+		return
+	}
+	item, err := h.sv.EventMembers.Put(r.Context(), eventID, userID, in.Role)
+	if err != nil {
+		h.log.Error().Err(err).Str("event_id", eventID).Str("user_id", userID).Msg("put event member failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to save event member")
+		return
+	}
+	item.Email, item.Name = u.Email, u.Name
+	respond.Single(w, http.StatusOK, item)
+}
+
+func (h *Handlers) DeleteEventMember(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "eventId")
+	userID := chi.URLParam(r, "userId")
+	if _, err := h.sv.Events.Get(r.Context(), orgID(r), eventID); err != nil {
+		respond.Error(w, http.StatusNotFound, "event not found")
+		return
+	}
+	if err := h.sv.EventMembers.Delete(r.Context(), eventID, userID); err != nil {
+		if errors.Is(err, repos.ErrNotFound) {
+			respond.Error(w, http.StatusNotFound, "event member not found")
+			return
+		}
+		h.log.Error().Err(err).Str("event_id", eventID).Str("user_id", userID).Msg("delete event member failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to delete event member")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// memberFilter returns the caller's user ID if they only see events they are a
+// member of, or "" if they see every event of the organization.
+func memberFilter(r *http.Request) string {
+	u, ok := auth.UserFromContext(r.Context())
+	if !ok || !auth.EventScoped(u.Role) {
+		return ""
+	}
+	return u.ID
+}
+
+// eventAccess reports whether the caller can see the event and whether they may
+// change it. Admins, staff and API keys see every event; planner users need a
+// membership, and only a "planner" membership allows changes.
+func (h *Handlers) eventAccess(r *http.Request, eventID string) (visible, writable bool, err error) {
+	userID := memberFilter(r)
+	if userID == "" {
+		return true, true, nil
+	}
+	role, err := h.sv.EventMembers.Role(r.Context(), eventID, userID)
+	if err != nil {
+		if errors.Is(err, repos.ErrNotFound) {
+			return false, false, nil
+		}
+		return false, false, err
+	}
+	return true, role == auth.EventRolePlanner, nil
+}
+
+// checkEventAccess writes 404 (with notFound as message) if the caller cannot see
+// the event, or 403 if write is set and they may only view it.
+func (h *Handlers) checkEventAccess(w http.ResponseWriter, r *http.Request, eventID, notFound string, write bool) bool {
+	visible, writable, err := h.eventAccess(r, eventID)
+	if err != nil {
+		h.log.Error().Err(err).Str("event_id", eventID).Msg("event access check failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to check event access")
+		return false
+	}
+	if !visible {
+		respond.Error(w, http.StatusNotFound, notFound)
+		return false
+	}
+	if write && !writable {
+		respond.Error(w, http.StatusForbidden, "read-only access to this event")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"planning-system/backend/internal/models"
+	"planning-system/backend/internal/repos"
+	"planning-system/backend/internal/services"
+	"planning-system/backend/pkg/respond"
+)
+
+func (h *Handlers) ListEvents(w http.ResponseWriter, r *http.Request) {
+	items, err := h.sv.Events.List(r.Context(), orgID(r), includeArchived(r), memberFilter(r))
+	if err != nil {
+		respond.Error(w, http.StatusInternalServerError, "failed to list events")
+		return
+	}
+	respond.List(w, http.StatusOK, items, nil)
+}
+
+func (h *Handlers) GetEvent(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "eventId")
+	item, err := h.sv.Events.Get(r.Context(), orgID(r), id)
+	if err != nil {
+		respond.Error(w, http.StatusNotFound, "event not found")
+		return
+	}
+	if !h.checkEventAccess(w, r, id, "event not found", false) {
+		return
+	}
+	respond.Single(w, http.StatusOK, item)
+}
+
+func (h *Handlers) CreateEvent(w http.ResponseWriter, r *http.Request) {
+	var in models.Event
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		respond.Error(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	if err := services.ValidateEvent(&in); err != nil {
+		h.rejectInvalid(w, err)
+		return
+	}
+	item, err := h.sv.Events.Create(r.Context(), orgID(r), in)
+	if err != nil {
+		h.log.Error().Err(err).Msg("create event failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to create event")
+		return
+	}
+	respond.Single(w, http.StatusCreated, item)
+}
+
+func (h *Handlers) UpdateEvent(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "eventId")
+	var in models.Event
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		respond.Error(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	if err := services.ValidateEvent(&in); err != nil {
+		h.rejectInvalid(w, err)
+		return
+	}
+	if !h.writableEvent(w, r, id) {
+		return
+	}
+	item, err := h.sv.Events.Update(r.Context(), orgID(r), id, in)
+	if err != nil {
+		if err == repos.ErrNotFound {
+			respond.Error(w, http.StatusNotFound, "event not found")
+			return
+		}
+		h.log.Error().Err(err).Str("event_id", id).Msg("update event failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to update event")
+		return
+	}
+	respond.Single(w, http.StatusOK, item)
+}
+
+func (h *Handlers) DeleteEvent(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "eventId")
+	if !h.writableEvent(w, r, id) {
+		return
+	}
+	if err := h.sv.Events.Delete(r.Context(), orgID(r), id); err != nil {
+		if err == repos.ErrNotFound {
+			respond.Error(w, http.StatusNotFound, "event not found")
+			return
+		}
+		h.log.Error().Err(err).Str("event_id", id).Msg("delete event failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to delete event")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CloneEvent copies the event and its whole program into a new date range.
+// Body: { name, description?, startDate, dropParticipants?, dropVehicleAssignments? }
+func (h *Handlers) CloneEvent(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "eventId")
+	var in models.CloneEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		respond.Error(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	if err := services.ValidateCloneEvent(in); err != nil {
+		h.rejectInvalid(w, err)
+		return
+	}
+	if !h.checkEventAccess(w, r, id, "event not found", false) {
+		return
+	}
+	item, err := h.sv.Events.Clone(r.Context(), orgID(r), id, in)
+	if err != nil {
+		if err == repos.ErrNotFound {
+			respond.Error(w, http.StatusNotFound, "event not found")
+			return
+		}
+		h.log.Error().Err(err).Str("event_id", id).Msg("clone event failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to clone event")
+		return
+	}
+	h.auditCloned(r, item.Event.ID)
+	respond.Single(w, http.StatusCreated, item)
+}
+
+// auditCloned records the creation of every day, block and movement of a cloned event.
+func (h *Handlers) auditCloned(r *http.Request, eventID string) {
+	days, err := h.sv.Days.List(r.Context(), orgID(r), eventID)
+	if err != nil {
+		h.log.Error().Err(err).Str("event_id", eventID).Msg("load cloned days failed")
+		return
+	}
+	dayIDs := make([]string, len(days))
+	for i, d := range days {
+		dayIDs[i] = d.ID
+		h.audit(r, "create", auditDay, d.ID, nil, d)
+	}
+	blocks, err := h.sv.Blocks.ListByDays(r.Context(), orgID(r), dayIDs)
+	if err != nil {
+		h.log.Error().Err(err).Str("event_id", eventID).Msg("load cloned blocks failed")
+		return
+	}
+	for _, b := range blocks {
+		h.audit(r, "create", auditBlock, b.ID, nil, b)
+	}
+	movements, err := h.sv.Movements.ListByDays(r.Context(), orgID(r), dayIDs)
+	if err != nil {
+		h.log.Error().Err(err).Str("event_id", eventID).Msg("load cloned movements failed")
+		return
+	}
+	for _, m := range movements {
+		h.audit(r, "create", auditMovement, m.ID, nil, m)
+	}
+}
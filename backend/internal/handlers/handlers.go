@@ -5,6 +5,7 @@ import (
 
 	"github.com/rs/zerolog"
 	"planning-system/backend/internal/services"
+	"planning-system/backend/internal/tenant"
 	"planning-system/backend/pkg/respond"
 )
 
@@ -21,4 +22,7 @@ func (h *Handlers) Health(w http.ResponseWriter, r *http.Request) {
 	respond.JSON(w, http.StatusOK, map[string]string{"status": "ok"})
 }
 
-
+// orgID returns the organization the request is scoped to.
+func orgID(r *http.Request) string {
+	return tenant.OrganizationID(r.Context())
+}
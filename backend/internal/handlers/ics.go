@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"planning-system/backend/internal/models"
+	"planning-system/backend/pkg/respond"
+)
+
+// ExportICS exports the event's blocks and movements as an iCalendar (RFC 5545) feed.
+func (h *Handlers) ExportICS(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "eventId")
+	event, ok := h.readableEvent(w, r, eventID)
+	if !ok {
+		return
+	}
+	days, err := h.sv.Itinerary.Itinerary(r.Context(), orgID(r), event.ID)
+	if err != nil {
+		respond.Error(w, http.StatusInternalServerError, "failed to build itinerary")
+		return
+	}
+	locByID, ok := h.locationNames(w, r)
+	if !ok {
+		return
+	}
+	cal := newCalendar(event.Name)
+	for _, d := range days {
+		for _, b := range d.Blocks {
+			cal.addBlock(b, locByID)
+		}
+		for _, m := range d.Movements {
+			cal.addMovement(m, locByID)
+		}
+	}
+	cal.write(w, "event.ics")
+}
+
+// AgendaICS exports a participant's agenda as an iCalendar (RFC 5545) feed.
+func (h *Handlers) AgendaICS(w http.ResponseWriter, r *http.Request) {
+	participantID := chi.URLParam(r, "participantId")
+	items, err := h.sv.Itinerary.Agenda(r.Context(), orgID(r), participantID, includeArchived(r), memberFilter(r))
+	if err != nil {
+		respond.Error(w, http.StatusInternalServerError, "failed to build agenda")
+		return
+	}
+	locByID, ok := h.locationNames(w, r)
+	if !ok {
+		return
+	}
+	cal := newCalendar("Agenda")
+	for _, it := range items {
+		cal.addBlock(it.Block, locByID)
+	}
+	cal.write(w, "agenda.ics")
+}
+
+func (h *Handlers) locationNames(w http.ResponseWriter, r *http.Request) (map[string]string, bool) {
+	locations, err := h.sv.Locations.List(r.Context(), orgID(r))
+	if err != nil {
+		respond.Error(w, http.StatusInternalServerError, "failed to load locations")
+		return nil, false
+	}
+	out := make(map[string]string, len(locations))
+	for _, l := range locations {
+		out[l.ID] = l.Name
+	}
+	return out, true
+}
+
+// calendar builds a VCALENDAR with one VEVENT per block or movement. All instants are written in UTC.
+type calendar struct {
+	b     strings.Builder
+	stamp string
+}
+
+func newCalendar(name string) *calendar {
+	c := &calendar{stamp: icsTime(time.Now())}
+	c.line("BEGIN:VCALENDAR")
+	c.line("VERSION:2.0")
+	c.line("PRODID:-//planning-system//itinerary//EN")
+	c.line("CALSCALE:GREGORIAN")
+	c.line("X-WR-CALNAME:" + icsEscape(name))
+	return c
+}
+
+func (c *calendar) addBlock(b models.Block, locByID map[string]string) {
+	if b.StartAt == nil {
+		return
+	}
+	location := ""
+	if b.LocationID != nil {
+		location = locByID[*b.LocationID]
+	}
+	c.event("block-"+b.ID, *b.StartAt, b.EndAt, b.Title, b.Description, location)
+}
+
+func (c *calendar) addMovement(m models.Movement, locByID map[string]string) {
+	if m.FromAt == nil {
+		return
+	}
+	desc := fmt.Sprintf("%s → %s", locByID[m.FromLocationID], locByID[m.ToLocationID])
+	if m.Description != "" {
+		desc += "\n" + m.Description
+	}
+	c.event("movement-"+m.ID, *m.FromAt, m.ToAt, m.Title, desc, locByID[m.FromLocationID])
+}
+
+func (c *calendar) event(uid string, start time.Time, end *time.Time, summary, description, location string) {
+	c.line("BEGIN:VEVENT")
+	c.line("UID:" + uid + "@planning-system")
+	c.line("DTSTAMP:" + c.stamp)
+	c.line("DTSTART:" + icsTime(start))
+	if end != nil && end.After(start) {
+		c.line("DTEND:" + icsTime(*end))
+	}
+	c.line("SUMMARY:" + icsEscape(summary))
+	if description != "" {
+		c.line("DESCRIPTION:" + icsEscape(description))
+	}
+	if location != "" {
+		c.line("LOCATION:" + icsEscape(location))
+	}
+	c.line("END:VEVENT")
+}
+
+// line writes a content line, folded at 75 octets as required by RFC 5545.
+func (c *calendar) line(s string) {
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && (s[cut]&0xC0) == 0x80 { // do not split UTF-8 sequences
+			cut--
+		}
+		c.b.WriteString(s[:cut] + "\r\n ")
+		s = s[cut:]
+		limit = 74 // continuation lines start with a space
+	}
+	c.b.WriteString(s + "\r\n")
+}
+
+func (c *calendar) write(w http.ResponseWriter, filename string) {
+	c.line("END:VCALENDAR")
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="`+filename+`"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(c.b.String()))
+}
+
+func icsTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+func icsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
@@ -4,25 +4,36 @@ import (
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	"planning-system/backend/internal/auth"
 	"planning-system/backend/pkg/respond"
 )
 
 func (h *Handlers) Itinerary(w http.ResponseWriter, r *http.Request) {
-	items, err := h.sv.Itinerary.Itinerary(r.Context())
+	eventID := chi.URLParam(r, "eventId")
+	if _, ok := h.readableEvent(w, r, eventID); !ok {
+		return
+	}
+	items, err := h.sv.Itinerary.Itinerary(r.Context(), orgID(r), eventID)
 	if err != nil {
 		respond.Error(w, http.StatusInternalServerError, "failed to build itinerary")
 		return
 	}
+	if audience(r) == auth.AudienceGuest {
+		redactItinerary(items)
+	}
 	respond.List(w, http.StatusOK, items, nil)
 }
 
 func (h *Handlers) Agenda(w http.ResponseWriter, r *http.Request) {
 	participantID := chi.URLParam(r, "participantId")
-	items, err := h.sv.Itinerary.Agenda(r.Context(), participantID)
+	items, err := h.sv.Itinerary.Agenda(r.Context(), orgID(r), participantID, includeArchived(r), memberFilter(r))
 	if err != nil {
 		respond.Error(w, http.StatusInternalServerError, "failed to build agenda")
 		return
 	}
+	if audience(r) == auth.AudienceGuest {
+		redactAgenda(items)
+	}
 	respond.List(w, http.StatusOK, items, nil)
 }
 
@@ -7,11 +7,12 @@ import (
 	"github.com/go-chi/chi/v5"
 	"planning-system/backend/internal/models"
 	"planning-system/backend/internal/repos"
+	"planning-system/backend/internal/services"
 	"planning-system/backend/pkg/respond"
 )
 
 func (h *Handlers) ListLocations(w http.ResponseWriter, r *http.Request) {
-	items, err := h.sv.Locations.List(r.Context())
+	items, err := h.sv.Locations.List(r.Context(), orgID(r))
 	if err != nil {
 		respond.Error(w, http.StatusInternalServerError, "failed to list locations")
 		return
@@ -21,11 +22,12 @@ func (h *Handlers) ListLocations(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handlers) GetLocation(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
-	item, err := h.sv.Locations.Get(r.Context(), id)
+	item, err := h.sv.Locations.Get(r.Context(), orgID(r), id)
 	if err != nil {
 		respond.Error(w, http.StatusNotFound, "location not found")
 		return
 	}
+	setETag(w, item.Version)
 	respond.Single(w, http.StatusOK, item)
 }
 
@@ -35,47 +37,103 @@ func (h *Handlers) CreateLocation(w http.ResponseWriter, r *http.Request) {
 		respond.Error(w, http.StatusBadRequest, "invalid json")
 		return
 	}
-	if in.Name == "" {
-		respond.Error(w, http.StatusBadRequest, "name is required")
+	if err := services.ValidateLocation(in); err != nil {
+		h.rejectInvalid(w, err)
 		return
 	}
-	item, err := h.sv.Locations.Create(r.Context(), in)
+	item, err := h.sv.Locations.Create(r.Context(), orgID(r), in)
 	if err != nil {
+		h.log.Error().Err(err).Msg("create location failed")
 		respond.Error(w, http.StatusInternalServerError, "failed to create")
 		return
 	}
+	h.audit(r, "create", auditLocation, item.ID, nil, item)
+	setETag(w, item.Version)
 	respond.Single(w, http.StatusCreated, item)
 }
 
 func (h *Handlers) UpdateLocation(w http.ResponseWriter, r *http.Request) {
-	id := chi.URLParam(r, "id")
 	var in models.Location
 	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
 		respond.Error(w, http.StatusBadRequest, "invalid json")
 		return
 	}
-	if in.Name == "" {
-		respond.Error(w, http.StatusBadRequest, "name is required")
+	before, err := h.sv.Locations.Get(r.Context(), orgID(r), chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusNotFound, "location not found")
+		return
+	}
+	h.saveLocation(w, r, before, in)
+}
+
+// PatchLocation applies a JSON merge patch (RFC 7396) to the location.
+func (h *Handlers) PatchLocation(w http.ResponseWriter, r *http.Request) {
+	before, err := h.sv.Locations.Get(r.Context(), orgID(r), chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusNotFound, "location not found")
+		return
+	}
+	var in models.Location
+	if _, ok := mergePatch(w, r, before, &in); !ok {
+		return
+	}
+	h.saveLocation(w, r, before, in)
+}
+
+// saveLocation validates in and stores it over the location before, subject to If-Match, and
+// records the update in the audit log.
+func (h *Handlers) saveLocation(w http.ResponseWriter, r *http.Request, before, in models.Location) {
+	if err := services.ValidateLocation(in); err != nil {
+		h.rejectInvalid(w, err)
 		return
 	}
-	item, err := h.sv.Locations.Update(r.Context(), id, in)
+	var ok bool
+	if in.Version, ok = matchVersion(w, r, before, before.Version); !ok {
+		return
+	}
+	id := before.ID
+	item, err := h.sv.Locations.Update(r.Context(), orgID(r), id, in)
 	if err != nil {
 		if err == repos.ErrNotFound {
 			respond.Error(w, http.StatusNotFound, "location not found")
 			return
 		}
+		if err == repos.ErrVersionMismatch {
+			if current, err := h.sv.Locations.Get(r.Context(), orgID(r), id); err == nil {
+				preconditionFailed(w, current, current.Version)
+				return
+			}
+		}
+		h.log.Error().Err(err).Str("location_id", id).Msg("update location failed")
 		respond.Error(w, http.StatusInternalServerError, "failed to update")
 		return
 	}
+	h.audit(r, "update", auditLocation, id, before, item)
+	setETag(w, item.Version)
 	respond.Single(w, http.StatusOK, item)
 }
 
+// DeleteLocation moves the location to the trash. A location that is still used answers 409
+// listing the references, unless ?force=true removes them or ?replaceWith=<id> reassigns them.
 func (h *Handlers) DeleteLocation(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
-	if err := h.sv.Locations.Delete(r.Context(), id); err != nil {
-		respond.Error(w, http.StatusInternalServerError, "failed to delete")
+	opts := deleteOptions(r)
+	if err := services.ValidateDeleteOptions(id, opts); err != nil {
+		h.rejectInvalid(w, err)
+		return
+	}
+	before, err := h.sv.Locations.Get(r.Context(), orgID(r), id)
+	if err != nil {
+		respond.Error(w, http.StatusNotFound, "location not found")
+		return
+	}
+	refs, changed, err := h.sv.Locations.Delete(r.Context(), orgID(r), id, opts)
+	if err != nil {
+		h.rejectDelete(w, err, "location", id)
 		return
 	}
+	h.audit(r, "delete", auditLocation, id, before, deleteImpact(opts, refs))
+	h.auditChanged(r, changed)
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -3,8 +3,10 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 
 	"github.com/go-chi/chi/v5"
+	"planning-system/backend/internal/auth"
 	"planning-system/backend/internal/models"
 	"planning-system/backend/internal/repos"
 	"planning-system/backend/pkg/respond"
@@ -12,22 +14,35 @@ import (
 
 func (h *Handlers) ListMovements(w http.ResponseWriter, r *http.Request) {
 	dayID := chi.URLParam(r, "dayId")
-	items, err := h.sv.Movements.ListByDay(r.Context(), dayID)
+	if !h.readableDay(w, r, dayID) {
+		return
+	}
+	items, err := h.sv.Movements.ListByDay(r.Context(), orgID(r), dayID)
 	if err != nil {
 		respond.Error(w, http.StatusInternalServerError, "failed to list movements")
 		return
 	}
+	if audience(r) == auth.AudienceGuest {
+		redactMovements(items)
+	}
 	respond.List(w, http.StatusOK, items, nil)
 }
 
 func (h *Handlers) GetMovement(w http.ResponseWriter, r *http.Request) {
 	dayID := chi.URLParam(r, "dayId")
 	id := chi.URLParam(r, "movementId")
-	item, err := h.sv.Movements.Get(r.Context(), dayID, id)
+	if !h.readableDay(w, r, dayID) {
+		return
+	}
+	item, err := h.sv.Movements.Get(r.Context(), orgID(r), dayID, id)
 	if err != nil {
 		respond.Error(w, http.StatusNotFound, "movement not found")
 		return
 	}
+	if audience(r) == auth.AudienceGuest {
+		item.Notes = ""
+	}
+	setETag(w, item.Version)
 	respond.Single(w, http.StatusOK, item)
 }
 
@@ -39,50 +54,133 @@ func (h *Handlers) CreateMovement(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	in.DayID = dayID
-	if in.Title == "" || in.FromTime == "" || (in.ToTimeType != "fixed" && in.ToTimeType != "driving") {
-		respond.Error(w, http.StatusBadRequest, "invalid movement payload")
+	if !h.writableDay(w, r, dayID) {
+		return
+	}
+	if err := h.sv.ValidateMovement(r.Context(), orgID(r), &in); err != nil {
+		h.rejectInvalid(w, err)
 		return
 	}
 	// optional capacity check for assignments-passengers omitted here
-	item, err := h.sv.Movements.Create(r.Context(), in)
+	item, err := h.sv.Movements.Create(r.Context(), orgID(r), in)
 	if err != nil {
-		respond.Error(w, http.StatusBadRequest, "failed to create movement")
+		if err == repos.ErrNotFound {
+			respond.Error(w, http.StatusNotFound, "day not found")
+			return
+		}
+		if missingReference(w, err) {
+			return
+		}
+		h.log.Error().Err(err).Str("day_id", dayID).Msg("create movement failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to create movement")
 		return
 	}
+	h.audit(r, "create", auditMovement, item.ID, nil, item)
+	setETag(w, item.Version)
 	respond.Single(w, http.StatusCreated, item)
 }
 
 func (h *Handlers) UpdateMovement(w http.ResponseWriter, r *http.Request) {
-	_ = chi.URLParam(r, "dayId")
+	dayID := chi.URLParam(r, "dayId")
 	id := chi.URLParam(r, "movementId")
 	var in models.Movement
 	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
 		respond.Error(w, http.StatusBadRequest, "invalid json")
 		return
 	}
-	if in.Title == "" || in.FromTime == "" || (in.ToTimeType != "fixed" && in.ToTimeType != "driving") {
-		respond.Error(w, http.StatusBadRequest, "invalid movement payload")
+	before, ok := h.writableMovement(w, r, dayID, id)
+	if !ok {
+		return
+	}
+	h.saveMovement(w, r, before, in, "update", repos.AllMovementRelations)
+}
+
+// PatchMovement applies a JSON merge patch (RFC 7396) to the movement. Its vehicle
+// assignments are only rewritten if the patch contains vehicleAssignments, which then
+// replaces them all.
+func (h *Handlers) PatchMovement(w http.ResponseWriter, r *http.Request) {
+	before, ok := h.writableMovement(w, r, chi.URLParam(r, "dayId"), chi.URLParam(r, "movementId"))
+	if !ok {
+		return
+	}
+	var in models.Movement
+	patch, ok := mergePatch(w, r, before, &in)
+	if !ok {
+		return
+	}
+	// The stored driving time comes back both as toTime and split into hours and
+	// minutes, and toTime wins on save: when the patch only changes the split, toTime
+	// is worked out again from it.
+	if in.ToTimeType == "driving" && !patch.has("toTime") && (patch.has("drivingTimeHours") || patch.has("drivingTimeMinutes")) {
+		in.ToTime = drivingTime(in.DrivingTimeHours, in.DrivingTimeMinutes)
+	}
+	h.saveMovement(w, r, before, in, "update", repos.MovementRelations{
+		VehicleAssignments: patch.has("vehicleAssignments"),
+	})
+}
+
+// drivingTime is the toTime of a driving movement, in minutes, for a driving time given
+// in hours and minutes, or "" if neither is set.
+func drivingTime(hours, minutes *int) string {
+	if hours == nil && minutes == nil {
+		return ""
+	}
+	total := 0
+	if hours != nil {
+		total += *hours * 60
+	}
+	if minutes != nil {
+		total += *minutes
+	}
+	return strconv.Itoa(total)
+}
+
+// saveMovement validates in and stores it over the movement before, subject to If-Match,
+// replacing the lists selected by rel, and records the change in the audit log under action.
+func (h *Handlers) saveMovement(w http.ResponseWriter, r *http.Request, before, in models.Movement, action string, rel repos.MovementRelations) {
+	if err := h.sv.ValidateMovement(r.Context(), orgID(r), &in); err != nil {
+		h.rejectInvalid(w, err)
+		return
+	}
+	var ok bool
+	if in.Version, ok = matchVersion(w, r, before, before.Version); !ok {
 		return
 	}
-	item, err := h.sv.Movements.Update(r.Context(), id, in)
+	id := before.ID
+	item, err := h.sv.Movements.Patch(r.Context(), orgID(r), id, in, rel)
 	if err != nil {
 		if err == repos.ErrNotFound {
 			respond.Error(w, http.StatusNotFound, "movement not found")
 			return
 		}
-		respond.Error(w, http.StatusBadRequest, "failed to update movement")
+		if err == repos.ErrVersionMismatch {
+			if current, err := h.sv.Movements.Get(r.Context(), orgID(r), before.DayID, id); err == nil {
+				preconditionFailed(w, current, current.Version)
+				return
+			}
+		}
+		if missingReference(w, err) {
+			return
+		}
+		h.log.Error().Err(err).Str("movement_id", id).Msg(action + " movement failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to "+action+" movement")
 		return
 	}
+	h.audit(r, action, auditMovement, id, before, item)
+	setETag(w, item.Version)
 	respond.Single(w, http.StatusOK, item)
 }
 
 func (h *Handlers) DeleteMovement(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "movementId")
-	if err := h.sv.Movements.Delete(r.Context(), id); err != nil {
+	before, ok := h.writableMovement(w, r, chi.URLParam(r, "dayId"), id)
+	if !ok {
+		return
+	}
+	if err := h.sv.Movements.Delete(r.Context(), orgID(r), id); err != nil {
 		respond.Error(w, http.StatusInternalServerError, "failed to delete movement")
 		return
 	}
+	h.audit(r, "delete", auditMovement, id, before, nil)
 	w.WriteHeader(http.StatusNoContent)
 }
-
-
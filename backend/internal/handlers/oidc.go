@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"planning-system/backend/internal/auth"
+	"planning-system/backend/internal/services"
+	"planning-system/backend/pkg/respond"
+)
+
+const ssoCookie = "oidc_login"
+
+// SSOLogin starts an OpenID Connect login by redirecting to the identity provider.
+func (h *Handlers) SSOLogin(w http.ResponseWriter, r *http.Request) {
+	redirectURL, state, err := h.sv.BeginSSOLogin(r.Context())
+	if err != nil {
+		if errors.Is(err, services.ErrSSODisabled) {
+			respond.Error(w, http.StatusNotFound, "single sign-on is not configured")
+			return
+		}
+		h.log.Error().Err(err).Msg("sso login failed")
+		respond.Error(w, http.StatusBadGateway, "identity provider unavailable")
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     ssoCookie,
+		Value:    state,
+		Path:     "/auth/oidc",
+		MaxAge:   int((10 * time.Minute).Seconds()),
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, redirectURL, http.StatusFound)
+}
+
+// SSOCallback completes the login when the identity provider redirects back with
+// ?code&state. The tokens are returned as JSON, or passed to the configured
+// frontend URL in the fragment (#accessToken=...&refreshToken=...).
+func (h *Handlers) SSOCallback(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{Name: ssoCookie, Path: "/auth/oidc", MaxAge: -1, HttpOnly: true, Secure: isHTTPS(r)})
+	q := r.URL.Query()
+	if idpErr := q.Get("error"); idpErr != "" {
+		respond.Error(w, http.StatusUnauthorized, "login refused by identity provider: "+idpErr)
+		return
+	}
+	loginState := ""
+	if c, err := r.Cookie(ssoCookie); err == nil {
+		loginState = c.Value
+	}
+	tokens, err := h.sv.CompleteSSOLogin(r.Context(), loginState, q.Get("state"), q.Get("code"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrSSODisabled):
+			respond.Error(w, http.StatusNotFound, "single sign-on is not configured")
+		case errors.Is(err, auth.ErrInvalidToken):
+			respond.Error(w, http.StatusBadRequest, "invalid or expired login state; start the login again")
+		case errors.Is(err, services.ErrSSONoAccount):
+			respond.Error(w, http.StatusForbidden, "no account is allowed for this identity")
+		default:
+			h.log.Error().Err(err).Msg("sso callback failed")
+			respond.Error(w, http.StatusUnauthorized, "single sign-on failed")
+		}
+		return
+	}
+	if target := h.sv.SSOPostLoginRedirect(); target != "" {
+		fragment := url.Values{
+			"accessToken":      {tokens.AccessToken},
+			"refreshToken":     {tokens.RefreshToken},
+			"tokenType":        {tokens.TokenType},
+			"expiresAt":        {strconv.FormatInt(tokens.ExpiresAt.Unix(), 10)},
+			"refreshExpiresAt": {strconv.FormatInt(tokens.RefreshExpiresAt.Unix(), 10)},
+		}
+		http.Redirect(w, r, target+"#"+fragment.Encode(), http.StatusFound)
+		return
+	}
+	respond.Single(w, http.StatusOK, tokens)
+}
+
+func isHTTPS(r *http.Request) bool {
+	return r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+}
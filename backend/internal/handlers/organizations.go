@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"planning-system/backend/internal/models"
+	"planning-system/backend/pkg/respond"
+)
+
+// ListOrganizations lists the organizations visible to the caller: their own.
+func (h *Handlers) ListOrganizations(w http.ResponseWriter, r *http.Request) {
+	item, err := h.sv.Organizations.Get(r.Context(), orgID(r))
+	if err != nil {
+		respond.Error(w, http.StatusInternalServerError, "failed to list organizations")
+		return
+	}
+	respond.List(w, http.StatusOK, []models.Organization{item}, nil)
+}
+
+func (h *Handlers) GetOrganization(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "orgId")
+	if id != orgID(r) {
+		respond.Error(w, http.StatusNotFound, "organization not found")
+		return
+	}
+	item, err := h.sv.Organizations.Get(r.Context(), id)
+	if err != nil {
+		respond.Error(w, http.StatusNotFound, "organization not found")
+		return
+	}
+	respond.Single(w, http.StatusOK, item)
+}
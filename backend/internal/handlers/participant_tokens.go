@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"planning-system/backend/internal/auth"
+	"planning-system/backend/internal/models"
+	"planning-system/backend/internal/repos"
+	"planning-system/backend/internal/services"
+	"planning-system/backend/pkg/respond"
+)
+
+func (h *Handlers) ListParticipantTokens(w http.ResponseWriter, r *http.Request) {
+	participantID := chi.URLParam(r, "id")
+	if _, err := h.sv.Participants.Get(r.Context(), orgID(r), participantID); err != nil {
+		respond.Error(w, http.StatusNotFound, "participant not found")
+		return
+	}
+	items, err := h.sv.ParticipantTokens.List(r.Context(), orgID(r), participantID)
+	if err != nil {
+		h.log.Error().Err(err).Str("participant_id", participantID).Msg("list access tokens failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to list access tokens")
+		return
+	}
+	respond.List(w, http.StatusOK, items, nil)
+}
+
+// IssueParticipantToken creates a magic link to the participant's agenda.
+// Body: { label?, expiresInHours? }. The token is only returned in this response.
+func (h *Handlers) IssueParticipantToken(w http.ResponseWriter, r *http.Request) {
+	participantID := chi.URLParam(r, "id")
+	var in models.IssueAccessTokenRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			respond.Error(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+	}
+	if err := services.ValidateIssueAccessToken(in); err != nil {
+		h.rejectInvalid(w, err)
+		return
+	}
+	createdBy := ""
+	if u, ok := auth.UserFromContext(r.Context()); ok {
+		createdBy = u.ID
+	}
+	item, err := h.sv.IssueParticipantToken(r.Context(), orgID(r), participantID, createdBy, in)
+	if err != nil {
+		if err == repos.ErrNotFound {
+			respond.Error(w, http.StatusNotFound, "participant not found")
+			return
+		}
+		h.log.Error().Err(err).Str("participant_id", participantID).Msg("issue access token failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to issue access token")
+		return
+	}
+	respond.Single(w, http.StatusCreated, item)
+}
+
+func (h *Handlers) RevokeParticipantToken(w http.ResponseWriter, r *http.Request) {
+	participantID := chi.URLParam(r, "id")
+	tokenID := chi.URLParam(r, "tokenId")
+	if err := h.sv.ParticipantTokens.Revoke(r.Context(), orgID(r), participantID, tokenID); err != nil {
+		if err == repos.ErrNotFound {
+			respond.Error(w, http.StatusNotFound, "access token not found")
+			return
+		}
+		h.log.Error().Err(err).Str("participant_id", participantID).Str("token_id", tokenID).Msg("revoke access token failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to revoke access token")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -7,13 +7,14 @@ import (
 	"github.com/go-chi/chi/v5"
 	"planning-system/backend/internal/models"
 	"planning-system/backend/internal/repos"
+	"planning-system/backend/internal/services"
 	"planning-system/backend/pkg/respond"
 )
 
 func (h *Handlers) ListParticipants(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query()
 	page := repos.ParsePagination(q.Get("limit"), q.Get("offset"))
-	items, total, err := h.sv.Participants.List(r.Context(), page, q.Get("search"), q.Get("role"))
+	items, total, err := h.sv.Participants.List(r.Context(), orgID(r), page, q.Get("search"), q.Get("role"))
 	if err != nil {
 		respond.Error(w, http.StatusInternalServerError, "failed to list participants")
 		return
@@ -23,11 +24,12 @@ func (h *Handlers) ListParticipants(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handlers) GetParticipant(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
-	item, err := h.sv.Participants.Get(r.Context(), id)
+	item, err := h.sv.Participants.Get(r.Context(), orgID(r), id)
 	if err != nil {
 		respond.Error(w, http.StatusNotFound, "participant not found")
 		return
 	}
+	setETag(w, item.Version)
 	respond.Single(w, http.StatusOK, item)
 }
 
@@ -37,47 +39,103 @@ func (h *Handlers) CreateParticipant(w http.ResponseWriter, r *http.Request) {
 		respond.Error(w, http.StatusBadRequest, "invalid json")
 		return
 	}
-	if in.Name == "" {
-		respond.Error(w, http.StatusBadRequest, "name is required")
+	if err := services.ValidateParticipant(in); err != nil {
+		h.rejectInvalid(w, err)
 		return
 	}
-	item, err := h.sv.Participants.Create(r.Context(), in)
+	item, err := h.sv.Participants.Create(r.Context(), orgID(r), in)
 	if err != nil {
+		h.log.Error().Err(err).Msg("create participant failed")
 		respond.Error(w, http.StatusInternalServerError, "failed to create")
 		return
 	}
+	h.audit(r, "create", auditParticipant, item.ID, nil, item)
+	setETag(w, item.Version)
 	respond.Single(w, http.StatusCreated, item)
 }
 
 func (h *Handlers) UpdateParticipant(w http.ResponseWriter, r *http.Request) {
-	id := chi.URLParam(r, "id")
 	var in models.Participant
 	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
 		respond.Error(w, http.StatusBadRequest, "invalid json")
 		return
 	}
-	if in.Name == "" {
-		respond.Error(w, http.StatusBadRequest, "name is required")
+	before, err := h.sv.Participants.Get(r.Context(), orgID(r), chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusNotFound, "participant not found")
+		return
+	}
+	h.saveParticipant(w, r, before, in)
+}
+
+// PatchParticipant applies a JSON merge patch (RFC 7396) to the participant.
+func (h *Handlers) PatchParticipant(w http.ResponseWriter, r *http.Request) {
+	before, err := h.sv.Participants.Get(r.Context(), orgID(r), chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusNotFound, "participant not found")
+		return
+	}
+	var in models.Participant
+	if _, ok := mergePatch(w, r, before, &in); !ok {
+		return
+	}
+	h.saveParticipant(w, r, before, in)
+}
+
+// saveParticipant validates in and stores it over the participant before, subject to If-Match, and
+// records the update in the audit log.
+func (h *Handlers) saveParticipant(w http.ResponseWriter, r *http.Request, before, in models.Participant) {
+	if err := services.ValidateParticipant(in); err != nil {
+		h.rejectInvalid(w, err)
 		return
 	}
-	item, err := h.sv.Participants.Update(r.Context(), id, in)
+	var ok bool
+	if in.Version, ok = matchVersion(w, r, before, before.Version); !ok {
+		return
+	}
+	id := before.ID
+	item, err := h.sv.Participants.Update(r.Context(), orgID(r), id, in)
 	if err != nil {
 		if err == repos.ErrNotFound {
 			respond.Error(w, http.StatusNotFound, "participant not found")
 			return
 		}
+		if err == repos.ErrVersionMismatch {
+			if current, err := h.sv.Participants.Get(r.Context(), orgID(r), id); err == nil {
+				preconditionFailed(w, current, current.Version)
+				return
+			}
+		}
+		h.log.Error().Err(err).Str("participant_id", id).Msg("update participant failed")
 		respond.Error(w, http.StatusInternalServerError, "failed to update")
 		return
 	}
+	h.audit(r, "update", auditParticipant, id, before, item)
+	setETag(w, item.Version)
 	respond.Single(w, http.StatusOK, item)
 }
 
+// DeleteParticipant moves the participant to the trash. A participant that is still used answers 409
+// listing the references, unless ?force=true removes them or ?replaceWith=<id> reassigns them.
 func (h *Handlers) DeleteParticipant(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
-	if err := h.sv.Participants.Delete(r.Context(), id); err != nil {
-		respond.Error(w, http.StatusInternalServerError, "failed to delete")
+	opts := deleteOptions(r)
+	if err := services.ValidateDeleteOptions(id, opts); err != nil {
+		h.rejectInvalid(w, err)
+		return
+	}
+	before, err := h.sv.Participants.Get(r.Context(), orgID(r), id)
+	if err != nil {
+		respond.Error(w, http.StatusNotFound, "participant not found")
+		return
+	}
+	refs, changed, err := h.sv.Participants.Delete(r.Context(), orgID(r), id, opts)
+	if err != nil {
+		h.rejectDelete(w, err, "participant", id)
 		return
 	}
+	h.audit(r, "delete", auditParticipant, id, before, deleteImpact(opts, refs))
+	h.auditChanged(r, changed)
 	w.WriteHeader(http.StatusNoContent)
 }
 
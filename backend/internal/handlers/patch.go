@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+
+	"planning-system/backend/pkg/respond"
+)
+
+// mergePatchType is the media type of a JSON merge patch (RFC 7396).
+const mergePatchType = "application/merge-patch+json"
+
+// patchDoc is a JSON merge patch. Its members are the fields it sets, or removes when null.
+type patchDoc map[string]any
+
+func (p patchDoc) has(field string) bool {
+	_, ok := p[field]
+	return ok
+}
+
+// mergePatch applies the JSON merge patch in the request body to the JSON of current
+// and decodes the result into dest. The body may be sent as application/merge-patch+json
+// or application/json. It returns the patch, or writes the error response and returns false.
+func mergePatch(w http.ResponseWriter, r *http.Request, current, dest any) (patchDoc, bool) {
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		mt, _, err := mime.ParseMediaType(ct)
+		if err != nil || (mt != mergePatchType && mt != "application/json") {
+			respond.Error(w, http.StatusUnsupportedMediaType, "PATCH takes a JSON merge patch ("+mergePatchType+")")
+			return nil, false
+		}
+	}
+	var patch patchDoc
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil || patch == nil {
+		respond.Error(w, http.StatusBadRequest, "merge patch must be a JSON object")
+		return nil, false
+	}
+	var doc map[string]any
+	raw, err := json.Marshal(current)
+	if err == nil {
+		err = json.Unmarshal(raw, &doc)
+	}
+	if err == nil {
+		raw, err = json.Marshal(mergeObject(doc, patch))
+	}
+	if err != nil {
+		respond.Error(w, http.StatusInternalServerError, "failed to apply patch")
+		return nil, false
+	}
+	if err := json.Unmarshal(raw, dest); err != nil {
+		respond.Error(w, http.StatusBadRequest, "invalid json")
+		return nil, false
+	}
+	return patch, true
+}
+
+// mergeObject merges patch into target as RFC 7396 describes: null members are removed,
+// objects are merged recursively, and any other value, arrays included, replaces the old one.
+func mergeObject(target, patch map[string]any) map[string]any {
+	if target == nil {
+		target = map[string]any{}
+	}
+	for k, v := range patch {
+		switch v := v.(type) {
+		case nil:
+			delete(target, k)
+		case map[string]any:
+			old, _ := target[k].(map[string]any)
+			target[k] = mergeObject(old, v)
+		default:
+			target[k] = v
+		}
+	}
+	return target
+}
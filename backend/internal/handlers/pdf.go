@@ -8,32 +8,42 @@ import (
 	"strings"
 	"time"
 
+	"planning-system/backend/internal/auth"
 	"planning-system/backend/internal/models"
 	"planning-system/backend/internal/repos"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/jung-kurt/gofpdf"
 )
 
 // ExportPDF generates a PDF export of the event with days, blocks, movements, participants, locations, and vehicles.
 func (h *Handlers) ExportPDF(w http.ResponseWriter, r *http.Request) {
+	eventID := chi.URLParam(r, "eventId")
+	event, ok := h.readableEvent(w, r, eventID)
+	if !ok {
+		return
+	}
 	// Fetch data
-	days, err := h.sv.Days.List(r.Context())
+	days, err := h.sv.Days.List(r.Context(), orgID(r), event.ID)
 	if err != nil {
 		http.Error(w, `{"error":"failed to load days"}`, http.StatusInternalServerError)
 		return
 	}
+	if audience(r) == auth.AudienceGuest {
+		redactDays(days)
+	}
 	// Participants (fetch many)
-	participants, _, err := h.sv.Participants.List(r.Context(), repos.PageParams{Limit: 10000, Offset: 0}, "", "")
+	participants, _, err := h.sv.Participants.List(r.Context(), orgID(r), repos.PageParams{Limit: 10000, Offset: 0}, "", "")
 	if err != nil {
 		http.Error(w, `{"error":"failed to load participants"}`, http.StatusInternalServerError)
 		return
 	}
-	locations, err := h.sv.Locations.List(r.Context())
+	locations, err := h.sv.Locations.List(r.Context(), orgID(r))
 	if err != nil {
 		http.Error(w, `{"error":"failed to load locations"}`, http.StatusInternalServerError)
 		return
 	}
-	vehicles, err := h.sv.Vehicles.List(r.Context())
+	vehicles, err := h.sv.Vehicles.List(r.Context(), orgID(r))
 	if err != nil {
 		http.Error(w, `{"error":"failed to load vehicles"}`, http.StatusInternalServerError)
 		return
@@ -99,6 +109,12 @@ func (h *Handlers) ExportPDF(w http.ResponseWriter, r *http.Request) {
 	pdf.SetY(12 + bannerHeight + 20)
 	pdf.SetFont("Times", "B", 18)
 	pdf.CellFormat(0, 10, "GLOBAL LINE BY LINE", "", 0, "C", false, 0, "")
+	pdf.Ln(10)
+	pdf.SetFont("Times", "", 14)
+	pdf.CellFormat(0, 8, event.Name, "", 0, "C", false, 0, "")
+	pdf.Ln(8)
+	pdf.SetFont("Times", "I", 11)
+	pdf.CellFormat(0, 6, "All times are local to "+event.TimeZone, "", 0, "C", false, 0, "")
 
 	// Section: Days
 	for _, d := range days {
@@ -144,6 +160,17 @@ func (h *Handlers) ExportPDF(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write(buf.Bytes())
 }
 
+// clockLabel formats an HH:mm time with a "(+N day)" suffix when it falls after the item's own day.
+func clockLabel(clock string, dayOffset int) string {
+	switch {
+	case dayOffset == 1:
+		return clock + " (+1 day)"
+	case dayOffset > 1:
+		return fmt.Sprintf("%s (+%d days)", clock, dayOffset)
+	}
+	return clock
+}
+
 func formatDateHeader(dateStr string) string {
 	// Parse date string (YYYY-MM-DD format)
 	t, err := time.Parse("2006-01-02", dateStr)
@@ -172,6 +199,13 @@ func drawBlockBox(pdf *gofpdf.Fpdf, b models.Block, locByID map[string]models.Lo
 	eventLine := "Event:            " + b.Title
 	pdf.MultiCell(pageWidth, 6, eventLine, "", "L", false)
 
+	// Overnight blocks: make the end on a later day explicit
+	if b.EndTime != "" && b.EndDayOffset > 0 {
+		pdf.SetX(x)
+		pdf.SetFont("Times", "I", 11)
+		pdf.MultiCell(pageWidth, 5, fmt.Sprintf("%s – %s", b.StartTime, clockLabel(b.EndTime, b.EndDayOffset)), "", "L", false)
+	}
+
 	// Add extra space between title and first sub-event
 	pdf.Ln(8)
 
@@ -183,7 +217,7 @@ func drawBlockBox(pdf *gofpdf.Fpdf, b models.Block, locByID map[string]models.Lo
 	for _, si := range b.ScheduleItems {
 		pdf.SetX(x) // Reset X position for each line
 		// Use same spacing as "Event:            " (12 spaces) between time and description
-		line := fmt.Sprintf("  %s            %s", si.Time, si.Description)
+		line := fmt.Sprintf("  %s            %s", clockLabel(si.Time, si.DayOffset), si.Description)
 		pdf.MultiCell(pageWidth, 5, line, "", "L", false)
 
 		// Staff/Guest Instructions - boxed
@@ -439,7 +473,7 @@ func drawMovementBox(pdf *gofpdf.Fpdf, m models.Movement, locByID map[string]mod
 	// Arrival - only show if fixed time
 	if m.ToTimeType == "fixed" {
 		pdf.SetX(x + padding)
-		pdf.MultiCell(pageWidth-(padding*2), 5, "Arrival at: "+m.ToTime, "", "L", false)
+		pdf.MultiCell(pageWidth-(padding*2), 5, "Arrival at: "+clockLabel(m.ToTime, m.ToDayOffset), "", "L", false)
 	}
 
 	// Vehicles section - two column layout
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+
+	"planning-system/backend/internal/auth"
+	"planning-system/backend/internal/models"
+)
+
+// audience returns who the response is for. Staff may ask for the guest view
+// with ?audience=guest (e.g. to print a guest agenda); guests always get it.
+func audience(r *http.Request) auth.Audience {
+	if r.URL.Query().Get("audience") == string(auth.AudienceGuest) {
+		return auth.AudienceGuest
+	}
+	return auth.AudienceFromContext(r.Context())
+}
+
+// Staff-only fields: block and movement notes, and schedule item staff
+// instructions and notes. The redact helpers clear them in place.
+
+func redactBlock(b *models.Block) {
+	b.Notes = ""
+	for i := range b.ScheduleItems {
+		b.ScheduleItems[i].StaffInstructions = ""
+		b.ScheduleItems[i].Notes = nil
+	}
+}
+
+func redactBlocks(blocks []models.Block) {
+	for i := range blocks {
+		redactBlock(&blocks[i])
+	}
+}
+
+func redactMovements(movements []models.Movement) {
+	for i := range movements {
+		movements[i].Notes = ""
+	}
+}
+
+func redactDay(d *models.Day) {
+	redactBlocks(d.Blocks)
+	redactMovements(d.Movements)
+}
+
+func redactDays(days []models.Day) {
+	for i := range days {
+		redactDay(&days[i])
+	}
+}
+
+func redactItinerary(days []models.ItineraryDay) {
+	for i := range days {
+		redactDay(&days[i].Day)
+		redactBlocks(days[i].Blocks)
+		redactMovements(days[i].Movements)
+	}
+}
+
+func redactAgenda(items []models.AgendaItem) {
+	for i := range items {
+		redactBlock(&items[i].Block)
+	}
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"planning-system/backend/internal/models"
+	"planning-system/backend/internal/repos"
+	"planning-system/backend/internal/services"
+	"planning-system/backend/pkg/respond"
+)
+
+// inUseMessages explain, by constraint, why a row that is still referenced cannot be deleted.
+var inUseMessages = map[string]string{
+	"fk_blocks_location":               "location is used by blocks",
+	"fk_movements_from_location":       "location is used by movements",
+	"fk_movements_to_location":         "location is used by movements",
+	"fk_vehicles_origination_location": "location is the origination location of vehicles",
+	"fk_block_templates_location":      "location is used by block templates",
+	"fk_vehicle_assignments_vehicle":   "vehicle is assigned to movements",
+	"fk_vehicle_assignments_driver":    "participant is assigned as a driver",
+	"fk_users_participant":             "participant is linked to a user account",
+}
+
+// missingReferenceFields name, by constraint, the payload field referencing a row that does not exist.
+// Payloads are validated before they are written, so these only show up when the row is deleted concurrently.
+var missingReferenceFields = map[string]string{
+	"fk_blocks_location":                           "locationId",
+	"fk_block_participants_participant":            "participantsIds",
+	"fk_block_advance_participants_participant":    "advanceParticipantIds",
+	"fk_block_met_by_participants_participant":     "metByParticipantIds",
+	"fk_movements_from_location":                   "fromLocationId",
+	"fk_movements_to_location":                     "toLocationId",
+	"fk_vehicle_assignments_vehicle":               "vehicleAssignments",
+	"fk_vehicle_assignments_driver":                "vehicleAssignments",
+	"fk_vehicle_assignment_passengers_participant": "vehicleAssignments",
+	"fk_vehicles_origination_location":             "originationLocationId",
+	"fk_block_templates_location":                  "locationId",
+}
+
+// inUse answers 409 if err shows that the row being deleted is still referenced.
+func inUse(w http.ResponseWriter, err error) bool {
+	constraint, ok := repos.ForeignKeyViolation(err)
+	if !ok {
+		return false
+	}
+	msg, known := inUseMessages[constraint]
+	if !known {
+		msg = "still referenced by other records"
+	}
+	respond.Error(w, http.StatusConflict, msg)
+	return true
+}
+
+// missingReference answers 422 if err shows that the payload references a row that does not exist.
+func missingReference(w http.ResponseWriter, err error) bool {
+	constraint, ok := repos.ForeignKeyViolation(err)
+	if !ok {
+		return false
+	}
+	field, known := missingReferenceFields[constraint]
+	if !known {
+		field = "id"
+	}
+	respond.Invalid(w, []respond.FieldError{{Field: field, Code: services.CodeNotFound, Message: field + " references a record that does not exist"}})
+	return true
+}
+
+// deleteOptions reads ?force=true and ?replaceWith=<id> of a location, vehicle or participant DELETE.
+func deleteOptions(r *http.Request) repos.DeleteOptions {
+	q := r.URL.Query()
+	return repos.DeleteOptions{Force: q.Get("force") == "true", ReplaceWith: q.Get("replaceWith")}
+}
+
+// rejectDelete answers a failed delete of a location, vehicle or participant (noun):
+// 409 listing the references if it is still in use, 422 if the replacement does not exist.
+func (h *Handlers) rejectDelete(w http.ResponseWriter, err error, noun, id string) {
+	var inUseErr *repos.InUseError
+	switch {
+	case errors.Is(err, repos.ErrNotFound):
+		respond.Error(w, http.StatusNotFound, noun+" not found")
+	case errors.As(err, &inUseErr):
+		respond.InUse(w, noun+" is still in use; delete with force=true to remove the references, or replaceWith=<id> to reassign them", inUseErr.References)
+	case errors.Is(err, repos.ErrReplacementNotFound):
+		respond.Invalid(w, []respond.FieldError{{Field: "replaceWith", Code: services.CodeNotFound, Message: "replaceWith references a " + noun + " that does not exist"}})
+	default:
+		h.log.Error().Err(err).Str(noun+"_id", id).Msg("delete " + noun + " failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to delete")
+	}
+}
+
+// deleteImpact is the audit snapshot of the references a delete replaced or removed.
+func deleteImpact(opts repos.DeleteOptions, refs []models.Reference) any {
+	if len(refs) == 0 {
+		return nil
+	}
+	return map[string]any{"force": opts.Force, "replaceWith": opts.ReplaceWith, "references": refs}
+}
+
+// auditChanged records an update of each block and movement whose references a delete
+// replaced or removed.
+func (h *Handlers) auditChanged(r *http.Request, changed []repos.ChangedRow) {
+	for _, c := range changed {
+		h.audit(r, "update", c.Type, c.ID, c.Before, c.After)
+	}
+}
+
+// rejectInvalid answers 422 listing the invalid fields of a *services.ValidationError,
+// and 500 for any other error (a failed reference lookup).
+func (h *Handlers) rejectInvalid(w http.ResponseWriter, err error) {
+	var verr *services.ValidationError
+	if errors.As(err, &verr) {
+		respond.Invalid(w, verr.Fields)
+		return
+	}
+	h.log.Error().Err(err).Msg("payload validation failed")
+	respond.Error(w, http.StatusInternalServerError, "failed to validate payload")
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"planning-system/backend/internal/auth"
+	"planning-system/backend/internal/models"
+	"planning-system/backend/internal/repos"
+	"planning-system/backend/pkg/respond"
+)
+
+func (h *Handlers) ListBlockRevisions(w http.ResponseWriter, r *http.Request) {
+	h.listRevisions(w, r, auditBlock)
+}
+
+func (h *Handlers) GetBlockRevision(w http.ResponseWriter, r *http.Request) {
+	h.getRevision(w, r, auditBlock)
+}
+
+// DiffBlockRevisions compares two revisions of a block. Query: from, to (versions).
+func (h *Handlers) DiffBlockRevisions(w http.ResponseWriter, r *http.Request) {
+	h.diffRevisions(w, r, auditBlock)
+}
+
+// RevertBlock puts the block back as it was at one of its revisions, schedule items and
+// participants included. The revert is saved like an update: it makes a new version,
+// honours If-Match and is validated again, so a revision naming a participant or
+// location that has since been deleted is refused with 422.
+func (h *Handlers) RevertBlock(w http.ResponseWriter, r *http.Request) {
+	version, ok := revisionVersion(w, r)
+	if !ok {
+		return
+	}
+	before, ok := h.writableBlock(w, r, chi.URLParam(r, "dayId"), chi.URLParam(r, "blockId"))
+	if !ok {
+		return
+	}
+	var in models.Block
+	if !h.loadRevision(w, r, auditBlock, before.ID, version, &in) {
+		return
+	}
+	// Items deleted or moved away since the revision come back as new items.
+	current := map[string]bool{}
+	for _, si := range before.ScheduleItems {
+		current[si.ID] = true
+	}
+	for i, si := range in.ScheduleItems {
+		if !current[si.ID] {
+			in.ScheduleItems[i].ID = ""
+		}
+	}
+	h.saveBlock(w, r, before, in, "revert", repos.AllBlockRelations)
+}
+
+func (h *Handlers) ListMovementRevisions(w http.ResponseWriter, r *http.Request) {
+	h.listRevisions(w, r, auditMovement)
+}
+
+func (h *Handlers) GetMovementRevision(w http.ResponseWriter, r *http.Request) {
+	h.getRevision(w, r, auditMovement)
+}
+
+// DiffMovementRevisions compares two revisions of a movement. Query: from, to (versions).
+func (h *Handlers) DiffMovementRevisions(w http.ResponseWriter, r *http.Request) {
+	h.diffRevisions(w, r, auditMovement)
+}
+
+// RevertMovement puts the movement back as it was at one of its revisions, vehicle
+// assignments included, in the same way as RevertBlock.
+func (h *Handlers) RevertMovement(w http.ResponseWriter, r *http.Request) {
+	version, ok := revisionVersion(w, r)
+	if !ok {
+		return
+	}
+	before, ok := h.writableMovement(w, r, chi.URLParam(r, "dayId"), chi.URLParam(r, "movementId"))
+	if !ok {
+		return
+	}
+	var in models.Movement
+	if !h.loadRevision(w, r, auditMovement, before.ID, version, &in) {
+		return
+	}
+	h.saveMovement(w, r, before, in, "revert", repos.AllMovementRelations)
+}
+
+func (h *Handlers) listRevisions(w http.ResponseWriter, r *http.Request, typ string) {
+	id, ok := h.readableRevisions(w, r, typ)
+	if !ok {
+		return
+	}
+	items, err := h.sv.Revisions.List(r.Context(), orgID(r), typ, id)
+	if err != nil {
+		h.log.Error().Err(err).Str("type", typ).Str("id", id).Msg("list revisions failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to list revisions")
+		return
+	}
+	respond.List(w, http.StatusOK, items, nil)
+}
+
+func (h *Handlers) getRevision(w http.ResponseWriter, r *http.Request, typ string) {
+	version, ok := revisionVersion(w, r)
+	if !ok {
+		return
+	}
+	id, ok := h.readableRevisions(w, r, typ)
+	if !ok {
+		return
+	}
+	item, err := h.sv.Revisions.Get(r.Context(), orgID(r), typ, id, version)
+	if err != nil {
+		h.rejectRevision(w, err, typ, id)
+		return
+	}
+	respond.Single(w, http.StatusOK, item)
+}
+
+func (h *Handlers) diffRevisions(w http.ResponseWriter, r *http.Request, typ string) {
+	from, errFrom := strconv.Atoi(r.URL.Query().Get("from"))
+	to, errTo := strconv.Atoi(r.URL.Query().Get("to"))
+	if errFrom != nil || errTo != nil || from <= 0 || to <= 0 {
+		respond.Error(w, http.StatusBadRequest, "from and to must be revision versions")
+		return
+	}
+	id, ok := h.readableRevisions(w, r, typ)
+	if !ok {
+		return
+	}
+	diff, err := h.sv.DiffRevisions(r.Context(), orgID(r), typ, id, from, to)
+	if err != nil {
+		h.rejectRevision(w, err, typ, id)
+		return
+	}
+	respond.Single(w, http.StatusOK, diff)
+}
+
+// readableRevisions checks that the block or movement named in the URL exists on the
+// day and that the caller can see it, and returns its id. Revisions hold staff-only
+// fields, so guests get 403.
+func (h *Handlers) readableRevisions(w http.ResponseWriter, r *http.Request, typ string) (string, bool) {
+	if auth.AudienceFromContext(r.Context()) == auth.AudienceGuest {
+		respond.Error(w, http.StatusForbidden, "insufficient permissions")
+		return "", false
+	}
+	dayID := chi.URLParam(r, "dayId")
+	if !h.readableDay(w, r, dayID) {
+		return "", false
+	}
+	var id string
+	var err error
+	switch typ {
+	case auditBlock:
+		id = chi.URLParam(r, "blockId")
+		_, err = h.sv.Blocks.Get(r.Context(), orgID(r), dayID, id)
+	case auditMovement:
+		id = chi.URLParam(r, "movementId")
+		_, err = h.sv.Movements.Get(r.Context(), orgID(r), dayID, id)
+	}
+	if err != nil {
+		respond.Error(w, http.StatusNotFound, typ+" not found")
+		return "", false
+	}
+	return id, true
+}
+
+// loadRevision decodes the snapshot of a revision into dest.
+func (h *Handlers) loadRevision(w http.ResponseWriter, r *http.Request, typ, id string, version int, dest any) bool {
+	rev, err := h.sv.Revisions.Get(r.Context(), orgID(r), typ, id, version)
+	if err == nil {
+		err = json.Unmarshal(rev.Snapshot, dest)
+	}
+	if err != nil {
+		h.rejectRevision(w, err, typ, id)
+		return false
+	}
+	return true
+}
+
+func (h *Handlers) rejectRevision(w http.ResponseWriter, err error, typ, id string) {
+	if errors.Is(err, repos.ErrNotFound) {
+		respond.Error(w, http.StatusNotFound, "revision not found")
+		return
+	}
+	h.log.Error().Err(err).Str("type", typ).Str("id", id).Msg("load revision failed")
+	respond.Error(w, http.StatusInternalServerError, "failed to load revision")
+}
+
+// revisionVersion reads the {version} URL parameter.
+func revisionVersion(w http.ResponseWriter, r *http.Request) (int, bool) {
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil || version <= 0 {
+		respond.Error(w, http.StatusNotFound, "revision not found")
+		return 0, false
+	}
+	return version, true
+}
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"planning-system/backend/internal/auth"
+	"planning-system/backend/internal/models"
+	"planning-system/backend/internal/repos"
+	"planning-system/backend/internal/services"
+	"planning-system/backend/pkg/respond"
+)
+
+// Schedule items are edited one at a time here, keeping their IDs. Each change is a
+// change of the block: it increments the block's version, which is the ETag of these
+// endpoints and what If-Match is checked against, and is audited as an update of the block.
+
+func (h *Handlers) ListScheduleItems(w http.ResponseWriter, r *http.Request) {
+	b, ok := h.readableBlock(w, r)
+	if !ok {
+		return
+	}
+	items := b.ScheduleItems
+	if items == nil {
+		items = []models.ScheduleItem{}
+	}
+	setETag(w, b.Version)
+	respond.List(w, http.StatusOK, items, nil)
+}
+
+func (h *Handlers) GetScheduleItem(w http.ResponseWriter, r *http.Request) {
+	b, ok := h.readableBlock(w, r)
+	if !ok {
+		return
+	}
+	item, ok := findScheduleItem(w, b, chi.URLParam(r, "itemId"))
+	if !ok {
+		return
+	}
+	setETag(w, b.Version)
+	respond.Single(w, http.StatusOK, item)
+}
+
+func (h *Handlers) CreateScheduleItem(w http.ResponseWriter, r *http.Request) {
+	var in models.ScheduleItem
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		respond.Error(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	before, ok := h.writableBlock(w, r, chi.URLParam(r, "dayId"), chi.URLParam(r, "blockId"))
+	if !ok {
+		return
+	}
+	if err := services.ValidateScheduleItem(in); err != nil {
+		h.rejectInvalid(w, err)
+		return
+	}
+	version, ok := matchVersion(w, r, before, before.Version)
+	if !ok {
+		return
+	}
+	item, err := h.sv.ScheduleItems.Create(r.Context(), orgID(r), before.ID, version, in)
+	if err != nil {
+		h.rejectScheduleItemChange(w, r, err, before)
+		return
+	}
+	h.scheduleItemChanged(w, r, http.StatusCreated, before, item.ID)
+}
+
+func (h *Handlers) UpdateScheduleItem(w http.ResponseWriter, r *http.Request) {
+	var in models.ScheduleItem
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		respond.Error(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	before, ok := h.writableBlock(w, r, chi.URLParam(r, "dayId"), chi.URLParam(r, "blockId"))
+	if !ok {
+		return
+	}
+	id := chi.URLParam(r, "itemId")
+	if _, ok := findScheduleItem(w, before, id); !ok {
+		return
+	}
+	if err := services.ValidateScheduleItem(in); err != nil {
+		h.rejectInvalid(w, err)
+		return
+	}
+	version, ok := matchVersion(w, r, before, before.Version)
+	if !ok {
+		return
+	}
+	if _, err := h.sv.ScheduleItems.Update(r.Context(), orgID(r), before.ID, id, version, in); err != nil {
+		h.rejectScheduleItemChange(w, r, err, before)
+		return
+	}
+	h.scheduleItemChanged(w, r, http.StatusOK, before, id)
+}
+
+func (h *Handlers) DeleteScheduleItem(w http.ResponseWriter, r *http.Request) {
+	before, ok := h.writableBlock(w, r, chi.URLParam(r, "dayId"), chi.URLParam(r, "blockId"))
+	if !ok {
+		return
+	}
+	id := chi.URLParam(r, "itemId")
+	if _, ok := findScheduleItem(w, before, id); !ok {
+		return
+	}
+	version, ok := matchVersion(w, r, before, before.Version)
+	if !ok {
+		return
+	}
+	if err := h.sv.ScheduleItems.Delete(r.Context(), orgID(r), before.ID, id, version); err != nil {
+		h.rejectScheduleItemChange(w, r, err, before)
+		return
+	}
+	if after, ok := h.auditBlockChange(r, before); ok {
+		setETag(w, after.Version)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MoveScheduleItem moves a schedule item to another block, keeping its ID, time and day
+// offset. Body: { "blockId", "dayId" }, where dayId defaults to the item's current day.
+// If-Match applies to the block the item leaves; the response is the item in its new
+// block, with that block's ETag.
+func (h *Handlers) MoveScheduleItem(w http.ResponseWriter, r *http.Request) {
+	var in models.MoveScheduleItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		respond.Error(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	before, ok := h.writableBlock(w, r, chi.URLParam(r, "dayId"), chi.URLParam(r, "blockId"))
+	if !ok {
+		return
+	}
+	id := chi.URLParam(r, "itemId")
+	if _, ok := findScheduleItem(w, before, id); !ok {
+		return
+	}
+	if err := services.ValidateMoveScheduleItem(in, before.ID); err != nil {
+		h.rejectInvalid(w, err)
+		return
+	}
+	if in.DayID == "" {
+		in.DayID = before.DayID
+	}
+	target, ok := h.writableBlock(w, r, in.DayID, in.BlockID)
+	if !ok {
+		return
+	}
+	version, ok := matchVersion(w, r, before, before.Version)
+	if !ok {
+		return
+	}
+	if err := h.sv.ScheduleItems.Move(r.Context(), orgID(r), before.ID, id, version, target.ID); err != nil {
+		h.rejectScheduleItemChange(w, r, err, before)
+		return
+	}
+	h.auditBlockChange(r, before)
+	h.scheduleItemChanged(w, r, http.StatusOK, target, id)
+}
+
+// readableBlock loads the block named in the URL for a caller who can see its day,
+// without staff-only fields for guests.
+func (h *Handlers) readableBlock(w http.ResponseWriter, r *http.Request) (models.Block, bool) {
+	dayID := chi.URLParam(r, "dayId")
+	if !h.readableDay(w, r, dayID) {
+		return models.Block{}, false
+	}
+	b, err := h.sv.Blocks.Get(r.Context(), orgID(r), dayID, chi.URLParam(r, "blockId"))
+	if err != nil {
+		respond.Error(w, http.StatusNotFound, "block not found")
+		return models.Block{}, false
+	}
+	if audience(r) == auth.AudienceGuest {
+		redactBlock(&b)
+	}
+	return b, true
+}
+
+func findScheduleItem(w http.ResponseWriter, b models.Block, id string) (models.ScheduleItem, bool) {
+	for _, it := range b.ScheduleItems {
+		if it.ID == id {
+			return it, true
+		}
+	}
+	respond.Error(w, http.StatusNotFound, "schedule item not found")
+	return models.ScheduleItem{}, false
+}
+
+func (h *Handlers) rejectScheduleItemChange(w http.ResponseWriter, r *http.Request, err error, block models.Block) {
+	switch {
+	case errors.Is(err, repos.ErrNotFound):
+		respond.Error(w, http.StatusNotFound, "schedule item not found")
+	case errors.Is(err, repos.ErrVersionMismatch):
+		if current, err := h.sv.Blocks.Get(r.Context(), orgID(r), block.DayID, block.ID); err == nil {
+			preconditionFailed(w, current, current.Version)
+			return
+		}
+		respond.Error(w, http.StatusInternalServerError, "failed to load block")
+	default:
+		h.log.Error().Err(err).Str("block_id", block.ID).Msg("change schedule item failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to change schedule item")
+	}
+}
+
+// scheduleItemChanged audits the change of the block before and answers with the
+// schedule item as now stored in it.
+func (h *Handlers) scheduleItemChanged(w http.ResponseWriter, r *http.Request, status int, before models.Block, id string) {
+	after, ok := h.auditBlockChange(r, before)
+	if !ok {
+		respond.Error(w, http.StatusInternalServerError, "failed to load block")
+		return
+	}
+	for _, it := range after.ScheduleItems {
+		if it.ID == id {
+			setETag(w, after.Version)
+			respond.Single(w, status, it)
+			return
+		}
+	}
+	respond.Error(w, http.StatusNotFound, "schedule item not found")
+}
+
+// auditBlockChange reloads the block after a change to its schedule items and records
+// the change in the audit log as an update of the block.
+func (h *Handlers) auditBlockChange(r *http.Request, before models.Block) (models.Block, bool) {
+	after, err := h.sv.Blocks.Get(r.Context(), orgID(r), before.DayID, before.ID)
+	if err != nil {
+		h.log.Error().Err(err).Str("block_id", before.ID).Msg("reload block failed")
+		h.audit(r, "update", auditBlock, before.ID, before, nil)
+		return models.Block{}, false
+	}
+	h.audit(r, "update", auditBlock, before.ID, before, after)
+	return after, true
+}
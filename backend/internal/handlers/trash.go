@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"planning-system/backend/internal/auth"
+	"planning-system/backend/internal/models"
+	"planning-system/backend/internal/repos"
+	"planning-system/backend/pkg/respond"
+)
+
+// trashPermissions are the permissions needed to see a type of trashed row, and to
+// restore or purge it: the same as for reading and deleting the row itself.
+var trashPermissions = map[string]struct{ read, write auth.Permission }{
+	auditLocation:    {auth.PermResourcesRead, auth.PermResourcesWrite},
+	auditVehicle:     {auth.PermResourcesRead, auth.PermResourcesWrite},
+	auditParticipant: {auth.PermResourcesRead, auth.PermResourcesWrite},
+	auditDay:         {auth.PermItineraryRead, auth.PermEventsWrite},
+	auditBlock:       {auth.PermItineraryRead, auth.PermBlocksWrite},
+	auditMovement:    {auth.PermItineraryRead, auth.PermMovementsWrite},
+}
+
+// ListTrash lists deleted rows the caller may see, most recently deleted first.
+// Query: type, limit, offset.
+func (h *Handlers) ListTrash(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var types []string
+	if typ := q.Get("type"); typ != "" {
+		perms, known := trashPermissions[typ]
+		if !known {
+			respond.Error(w, http.StatusBadRequest, "unknown type")
+			return
+		}
+		if !auth.Granted(r.Context(), perms.read) {
+			respond.Error(w, http.StatusForbidden, "insufficient permissions")
+			return
+		}
+		types = []string{typ}
+	} else {
+		for typ, perms := range trashPermissions {
+			if auth.Granted(r.Context(), perms.read) {
+				types = append(types, typ)
+			}
+		}
+		if len(types) == 0 {
+			respond.Error(w, http.StatusForbidden, "insufficient permissions")
+			return
+		}
+	}
+	page := repos.ParsePagination(q.Get("limit"), q.Get("offset"))
+	items, total, err := h.sv.Trash.List(r.Context(), orgID(r), types, memberFilter(r), page)
+	if err != nil {
+		h.log.Error().Err(err).Msg("list trash failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to list trash")
+		return
+	}
+	respond.List(w, http.StatusOK, items, &total)
+}
+
+// RestoreTrash takes a deleted row out of the trash together with its child rows.
+func (h *Handlers) RestoreTrash(w http.ResponseWriter, r *http.Request) {
+	it, ok := h.writableTrash(w, r)
+	if !ok {
+		return
+	}
+	if err := h.sv.Trash.Restore(r.Context(), orgID(r), it.Type, it.ID); err != nil {
+		switch {
+		case errors.Is(err, repos.ErrNotFound):
+			respond.Error(w, http.StatusNotFound, "item not found in trash")
+		case errors.Is(err, repos.ErrParentDeleted):
+			respond.Error(w, http.StatusConflict, "the day of this "+it.Type+" is in the trash; restore the day first")
+		case errors.Is(err, repos.ErrDateTaken):
+			respond.Error(w, http.StatusConflict, "the event already has another day on "+it.Date)
+		default:
+			h.log.Error().Err(err).Str("type", it.Type).Str("id", it.ID).Msg("restore failed")
+			respond.Error(w, http.StatusInternalServerError, "failed to restore")
+		}
+		return
+	}
+	h.audit(r, "restore", it.Type, it.ID, nil, it)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PurgeTrash permanently deletes a row from the trash.
+func (h *Handlers) PurgeTrash(w http.ResponseWriter, r *http.Request) {
+	it, ok := h.writableTrash(w, r)
+	if !ok {
+		return
+	}
+	if err := h.sv.Trash.Purge(r.Context(), orgID(r), it.Type, it.ID); err != nil {
+		if errors.Is(err, repos.ErrNotFound) {
+			respond.Error(w, http.StatusNotFound, "item not found in trash")
+			return
+		}
+		if inUse(w, err) {
+			return
+		}
+		h.log.Error().Err(err).Str("type", it.Type).Str("id", it.ID).Msg("purge failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to purge")
+		return
+	}
+	h.audit(r, "purge", it.Type, it.ID, it, nil)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writableTrash loads the trashed row named by the {type} and {id} URL parameters and
+// checks that the caller may restore or purge it. Rows of an event are subject to the
+// same membership and archiving rules as the event itself.
+func (h *Handlers) writableTrash(w http.ResponseWriter, r *http.Request) (models.TrashItem, bool) {
+	typ := chi.URLParam(r, "type")
+	perms, known := trashPermissions[typ]
+	if !known {
+		respond.Error(w, http.StatusNotFound, "item not found in trash")
+		return models.TrashItem{}, false
+	}
+	if !auth.Granted(r.Context(), perms.write) {
+		respond.Error(w, http.StatusForbidden, "insufficient permissions")
+		return models.TrashItem{}, false
+	}
+	it, err := h.sv.Trash.Get(r.Context(), orgID(r), typ, chi.URLParam(r, "id"))
+	if err != nil {
+		if errors.Is(err, repos.ErrNotFound) {
+			respond.Error(w, http.StatusNotFound, "item not found in trash")
+			return models.TrashItem{}, false
+		}
+		h.log.Error().Err(err).Str("type", typ).Msg("get trash item failed")
+		respond.Error(w, http.StatusInternalServerError, "failed to load trash item")
+		return models.TrashItem{}, false
+	}
+	if it.EventID != "" && !h.writableEvent(w, r, it.EventID) {
+		return models.TrashItem{}, false
+	}
+	return it, true
+}
@@ -7,11 +7,12 @@ import (
 	"github.com/go-chi/chi/v5"
 	"planning-system/backend/internal/models"
 	"planning-system/backend/internal/repos"
+	"planning-system/backend/internal/services"
 	"planning-system/backend/pkg/respond"
 )
 
 func (h *Handlers) ListVehicles(w http.ResponseWriter, r *http.Request) {
-	items, err := h.sv.Vehicles.List(r.Context())
+	items, err := h.sv.Vehicles.List(r.Context(), orgID(r))
 	if err != nil {
 		respond.Error(w, http.StatusInternalServerError, "failed to list vehicles")
 		return
@@ -21,11 +22,12 @@ func (h *Handlers) ListVehicles(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handlers) GetVehicle(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
-	item, err := h.sv.Vehicles.Get(r.Context(), id)
+	item, err := h.sv.Vehicles.Get(r.Context(), orgID(r), id)
 	if err != nil {
 		respond.Error(w, http.StatusNotFound, "vehicle not found")
 		return
 	}
+	setETag(w, item.Version)
 	respond.Single(w, http.StatusOK, item)
 }
 
@@ -35,55 +37,109 @@ func (h *Handlers) CreateVehicle(w http.ResponseWriter, r *http.Request) {
 		respond.Error(w, http.StatusBadRequest, "invalid json")
 		return
 	}
-	if in.Label == "" {
-		respond.Error(w, http.StatusBadRequest, "label required")
+	if err := h.sv.ValidateVehicle(r.Context(), orgID(r), &in); err != nil {
+		h.rejectInvalid(w, err)
 		return
 	}
-	if in.Capacity != nil && *in.Capacity < 0 {
-		respond.Error(w, http.StatusBadRequest, "capacity must be non-negative")
-		return
-	}
-	item, err := h.sv.Vehicles.Create(r.Context(), in)
+	item, err := h.sv.Vehicles.Create(r.Context(), orgID(r), in)
 	if err != nil {
+		if missingReference(w, err) {
+			return
+		}
+		h.log.Error().Err(err).Msg("create vehicle failed")
 		respond.Error(w, http.StatusInternalServerError, "failed to create")
 		return
 	}
+	h.audit(r, "create", auditVehicle, item.ID, nil, item)
+	setETag(w, item.Version)
 	respond.Single(w, http.StatusCreated, item)
 }
 
 func (h *Handlers) UpdateVehicle(w http.ResponseWriter, r *http.Request) {
-	id := chi.URLParam(r, "id")
 	var in models.Vehicle
 	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
 		respond.Error(w, http.StatusBadRequest, "invalid json")
 		return
 	}
-	if in.Label == "" {
-		respond.Error(w, http.StatusBadRequest, "label required")
+	before, err := h.sv.Vehicles.Get(r.Context(), orgID(r), chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusNotFound, "vehicle not found")
+		return
+	}
+	h.saveVehicle(w, r, before, in)
+}
+
+// PatchVehicle applies a JSON merge patch (RFC 7396) to the vehicle.
+func (h *Handlers) PatchVehicle(w http.ResponseWriter, r *http.Request) {
+	before, err := h.sv.Vehicles.Get(r.Context(), orgID(r), chi.URLParam(r, "id"))
+	if err != nil {
+		respond.Error(w, http.StatusNotFound, "vehicle not found")
+		return
+	}
+	var in models.Vehicle
+	if _, ok := mergePatch(w, r, before, &in); !ok {
+		return
+	}
+	h.saveVehicle(w, r, before, in)
+}
+
+// saveVehicle validates in and stores it over the vehicle before, subject to If-Match, and
+// records the update in the audit log.
+func (h *Handlers) saveVehicle(w http.ResponseWriter, r *http.Request, before, in models.Vehicle) {
+	if err := h.sv.ValidateVehicle(r.Context(), orgID(r), &in); err != nil {
+		h.rejectInvalid(w, err)
 		return
 	}
-	if in.Capacity != nil && *in.Capacity < 0 {
-		respond.Error(w, http.StatusBadRequest, "capacity must be non-negative")
+	var ok bool
+	if in.Version, ok = matchVersion(w, r, before, before.Version); !ok {
 		return
 	}
-	item, err := h.sv.Vehicles.Update(r.Context(), id, in)
+	id := before.ID
+	item, err := h.sv.Vehicles.Update(r.Context(), orgID(r), id, in)
 	if err != nil {
 		if err == repos.ErrNotFound {
 			respond.Error(w, http.StatusNotFound, "vehicle not found")
 			return
 		}
+		if err == repos.ErrVersionMismatch {
+			if current, err := h.sv.Vehicles.Get(r.Context(), orgID(r), id); err == nil {
+				preconditionFailed(w, current, current.Version)
+				return
+			}
+		}
+		if missingReference(w, err) {
+			return
+		}
+		h.log.Error().Err(err).Str("vehicle_id", id).Msg("update vehicle failed")
 		respond.Error(w, http.StatusInternalServerError, "failed to update")
 		return
 	}
+	h.audit(r, "update", auditVehicle, id, before, item)
+	setETag(w, item.Version)
 	respond.Single(w, http.StatusOK, item)
 }
 
+// DeleteVehicle moves the vehicle to the trash. A vehicle that is still used answers 409
+// listing the references, unless ?force=true removes them or ?replaceWith=<id> reassigns them.
 func (h *Handlers) DeleteVehicle(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
-	if err := h.sv.Vehicles.Delete(r.Context(), id); err != nil {
-		respond.Error(w, http.StatusInternalServerError, "failed to delete")
+	opts := deleteOptions(r)
+	if err := services.ValidateDeleteOptions(id, opts); err != nil {
+		h.rejectInvalid(w, err)
+		return
+	}
+	before, err := h.sv.Vehicles.Get(r.Context(), orgID(r), id)
+	if err != nil {
+		respond.Error(w, http.StatusNotFound, "vehicle not found")
+		return
+	}
+	refs, changed, err := h.sv.Vehicles.Delete(r.Context(), orgID(r), id, opts)
+	if err != nil {
+		h.rejectDelete(w, err, "vehicle", id)
 		return
 	}
+	h.audit(r, "delete", auditVehicle, id, before, deleteImpact(opts, refs))
+	h.auditChanged(r, changed)
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"planning-system/backend/pkg/respond"
+)
+
+// setETag sends the row version as a strong entity tag.
+func setETag(w http.ResponseWriter, version int) {
+	if version > 0 {
+		w.Header().Set("ETag", `"`+strconv.Itoa(version)+`"`)
+	}
+}
+
+// ifMatch returns the version required by the If-Match header: 0 if there is none or it is "*".
+// ok is false if the header is not a single strong entity tag holding a version.
+func ifMatch(r *http.Request) (version int, ok bool) {
+	v := strings.TrimSpace(r.Header.Get("If-Match"))
+	if v == "" || v == "*" {
+		return 0, true
+	}
+	if len(v) < 3 || v[0] != '"' || v[len(v)-1] != '"' {
+		return 0, false
+	}
+	n, err := strconv.Atoi(v[1 : len(v)-1])
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// matchVersion checks the If-Match header against the version of the current item and
+// returns the version the update must apply to (0 if the request is unconditional).
+// If the header names another version it answers 412 with the current item and returns false.
+func matchVersion[T any](w http.ResponseWriter, r *http.Request, current T, version int) (int, bool) {
+	want, ok := ifMatch(r)
+	if ok && (want == 0 || want == version) {
+		return want, true
+	}
+	preconditionFailed(w, current, version)
+	return 0, false
+}
+
+// preconditionFailed answers 412 with the current representation and its ETag.
+func preconditionFailed[T any](w http.ResponseWriter, current T, version int) {
+	setETag(w, version)
+	respond.PreconditionFailed(w, "the item has changed since it was read; apply your edit to the current version", current)
+}
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"planning-system/backend/internal/auth"
+	"planning-system/backend/internal/models"
+	"planning-system/backend/internal/services"
+	"planning-system/backend/internal/tenant"
+	"planning-system/backend/pkg/respond"
+)
+
+// APIKeyHeader may carry an API key instead of the Authorization header.
+const APIKeyHeader = "X-API-Key"
+
+// Authenticate requires a valid "Authorization: Bearer <access token or API key>"
+// header, or an X-API-Key header. The authenticated user or API key and its
+// organization are stored in the request context.
+func Authenticate(sv *services.Services) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := auth.BearerToken(r)
+			if token == "" {
+				token = r.Header.Get(APIKeyHeader)
+			}
+			if token == "" {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				respond.Error(w, http.StatusUnauthorized, "authentication required")
+				return
+			}
+			if strings.HasPrefix(token, auth.APIKeyPrefix) {
+				k, err := sv.AuthenticateAPIKey(r.Context(), token)
+				if err != nil {
+					if !errors.Is(err, auth.ErrInvalidToken) {
+						respond.Error(w, http.StatusInternalServerError, "failed to verify API key")
+						return
+					}
+					w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+					respond.Error(w, http.StatusUnauthorized, "invalid, expired or revoked API key")
+					return
+				}
+				ctx := auth.WithAPIKey(r.Context(), k)
+				ctx = tenant.WithOrganization(ctx, k.OrganizationID)
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+			u, err := sv.Authenticate(r.Context(), token)
+			if err != nil {
+				if !errors.Is(err, auth.ErrInvalidToken) {
+					respond.Error(w, http.StatusInternalServerError, "failed to verify token")
+					return
+				}
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				respond.Error(w, http.StatusUnauthorized, "invalid or expired token")
+				return
+			}
+			ctx := auth.WithUser(r.Context(), u)
+			ctx = tenant.WithOrganization(ctx, u.OrganizationID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// AuthenticateAgenda is Authenticate for the participant agenda routes, which also
+// accept a participant access (magic-link) token, either as ?token= or as the bearer
+// token. Such a token acts as a participant user limited to its own agenda.
+func AuthenticateAgenda(sv *services.Services) func(next http.Handler) http.Handler {
+	session := Authenticate(sv)
+	return func(next http.Handler) http.Handler {
+		withSession := session(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := r.URL.Query().Get("token")
+			if token == "" {
+				token = auth.BearerToken(r)
+			}
+			if !strings.HasPrefix(token, auth.ParticipantTokenPrefix) {
+				withSession.ServeHTTP(w, r)
+				return
+			}
+			t, err := sv.AuthenticateParticipantToken(r.Context(), token)
+			if err != nil {
+				if !errors.Is(err, auth.ErrInvalidToken) {
+					respond.Error(w, http.StatusInternalServerError, "failed to verify token")
+					return
+				}
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				respond.Error(w, http.StatusUnauthorized, "invalid, expired or revoked link")
+				return
+			}
+			participantID := t.ParticipantID
+			u := models.User{
+				OrganizationID: t.OrganizationID,
+				Role:           auth.RoleParticipant,
+				ParticipantID:  &participantID,
+			}
+			ctx := auth.WithUser(r.Context(), u)
+			ctx = tenant.WithOrganization(ctx, t.OrganizationID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
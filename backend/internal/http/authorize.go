@@ -0,0 +1,52 @@
+package http
+
+import (
+	"net/http"
+
+	"planning-system/backend/internal/auth"
+	"planning-system/backend/pkg/respond"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Require lets the request through only if the authenticated user or API key
+// holds every given permission.
+func Require(perms ...auth.Permission) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !auth.Authenticated(r.Context()) {
+				respond.Error(w, http.StatusUnauthorized, "authentication required")
+				return
+			}
+			for _, p := range perms {
+				if !auth.Granted(r.Context(), p) {
+					respond.Error(w, http.StatusForbidden, "insufficient permissions")
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireOwnAgenda allows callers with itinerary read access to every agenda, and
+// users with only PermOwnAgenda to the agenda of their own participant record.
+func RequireOwnAgenda(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !auth.Authenticated(r.Context()) {
+			respond.Error(w, http.StatusUnauthorized, "authentication required")
+			return
+		}
+		if auth.Granted(r.Context(), auth.PermItineraryRead) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		u, _ := auth.UserFromContext(r.Context())
+		own := u.ParticipantID != nil && *u.ParticipantID == chi.URLParam(r, "participantId")
+		if !own || !auth.HasPermission(u.Role, auth.PermOwnAgenda) {
+			respond.Error(w, http.StatusForbidden, "insufficient permissions")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
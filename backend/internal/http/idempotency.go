@@ -0,0 +1,146 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"planning-system/backend/internal/auth"
+	"planning-system/backend/internal/repos"
+	"planning-system/backend/internal/services"
+	"planning-system/backend/internal/tenant"
+	"planning-system/backend/pkg/respond"
+
+	"github.com/rs/zerolog"
+)
+
+// IdempotencyKeyHeader lets a client retry a create without creating twice.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// ReplayedHeader is set on a response replayed for a retried Idempotency-Key.
+const ReplayedHeader = "Idempotent-Replayed"
+
+// idempotencyKeyTTL is how long a key and its response are kept.
+const idempotencyKeyTTL = 24 * time.Hour
+
+const maxIdempotencyKeyLength = 255
+
+// Idempotent makes a create endpoint honour the Idempotency-Key header. The first
+// request with a key is processed and its response stored with a hash of the request;
+// a retry with the same key and request gets the stored response again, a request
+// reusing the key for something else gets 422, and a retry while the first request
+// is still running gets 409. Keys are per caller. Server errors are not stored, so the
+// request can be retried with the same key. Requests without the header are unaffected.
+func Idempotent(sv *services.Services, logger zerolog.Logger) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				respond.Error(w, http.StatusBadRequest, "Idempotency-Key must be at most 255 characters")
+				return
+			}
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				respond.Error(w, http.StatusBadRequest, "failed to read request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			h := sha256.New()
+			h.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+			h.Write(body)
+			hash := hex.EncodeToString(h.Sum(nil))
+
+			orgID, actor := tenant.OrganizationID(r.Context()), idempotencyActor(r.Context())
+			stored, claimed, err := sv.Idempotency.Claim(r.Context(), orgID, actor, key, hash, idempotencyKeyTTL)
+			if err != nil && !errors.Is(err, repos.ErrNotFound) {
+				logger.Error().Err(err).Msg("claim idempotency key failed")
+				respond.Error(w, http.StatusInternalServerError, "failed to check Idempotency-Key")
+				return
+			}
+			if !claimed {
+				switch {
+				case err != nil || stored.StatusCode == 0:
+					respond.Error(w, http.StatusConflict, "a request with this Idempotency-Key is still being processed; retry later")
+				case stored.RequestHash != hash:
+					respond.Error(w, http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request")
+				default:
+					if stored.ContentType != "" {
+						w.Header().Set("Content-Type", stored.ContentType)
+					}
+					if stored.ETag != "" {
+						w.Header().Set("ETag", stored.ETag)
+					}
+					w.Header().Set(ReplayedHeader, "true")
+					w.WriteHeader(stored.StatusCode)
+					_, _ = w.Write(stored.Body)
+				}
+				return
+			}
+
+			// The key is settled even if the client goes away or the handler panics.
+			ctx := context.WithoutCancel(r.Context())
+			rec := &recordingWriter{ResponseWriter: w, status: http.StatusOK}
+			completed := false
+			defer func() {
+				if completed {
+					return
+				}
+				if err := sv.Idempotency.Release(ctx, orgID, actor, key); err != nil {
+					logger.Error().Err(err).Msg("release idempotency key failed")
+				}
+			}()
+			next.ServeHTTP(rec, r)
+			if rec.status >= http.StatusInternalServerError {
+				return
+			}
+			resp := repos.StoredResponse{
+				StatusCode:  rec.status,
+				ContentType: rec.Header().Get("Content-Type"),
+				ETag:        rec.Header().Get("ETag"),
+				Body:        rec.body.Bytes(),
+			}
+			if err := sv.Idempotency.Complete(ctx, orgID, actor, key, resp); err != nil {
+				logger.Error().Err(err).Msg("store idempotent response failed")
+				return
+			}
+			completed = true
+		})
+	}
+}
+
+// idempotencyActor scopes keys to the calling user or API key.
+func idempotencyActor(ctx context.Context) string {
+	if u, ok := auth.UserFromContext(ctx); ok {
+		return "user:" + u.ID
+	}
+	if k, ok := auth.APIKeyFromContext(ctx); ok {
+		return "api_key:" + k.ID
+	}
+	return ""
+}
+
+// recordingWriter keeps a copy of the response it writes.
+type recordingWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *recordingWriter) WriteHeader(statusCode int) {
+	w.status = statusCode
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
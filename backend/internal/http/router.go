@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"time"
 
+	"planning-system/backend/internal/auth"
 	"planning-system/backend/internal/config"
 	"planning-system/backend/internal/handlers"
 	"planning-system/backend/internal/services"
@@ -20,9 +21,9 @@ func NewRouter(cfg config.Config, logger zerolog.Logger, pool *pgxpool.Pool) htt
 	// CORS enabled - allow all origins
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "If-Match", IdempotencyKeyHeader, OrganizationHeader, APIKeyHeader},
+		ExposedHeaders:   []string{"Link", "ETag", ReplayedHeader},
 		AllowCredentials: false, // Must be false when using wildcard origin
 		MaxAge:           300,
 	}))
@@ -31,81 +32,197 @@ func NewRouter(cfg config.Config, logger zerolog.Logger, pool *pgxpool.Pool) htt
 	r.Use(Timeout(60 * time.Second))
 
 	// Services and handlers
-	svcs := services.New(pool)
+	svcs := services.New(pool, cfg)
 	h := handlers.New(logger, svcs)
 
 	// Health
 	r.Get("/health", h.Health)
 
-	// Locations
-	r.Route("/locations", func(r chi.Router) {
-		r.Get("/", h.ListLocations)
-		r.Post("/", h.CreateLocation)
-		r.Route("/{id}", func(r chi.Router) {
-			r.Get("/", h.GetLocation)
-			r.Put("/", h.UpdateLocation)
-			r.Delete("/", h.DeleteLocation)
+	// Authentication (public)
+	r.Post("/auth/login", h.Login)
+	r.Post("/auth/refresh", h.Refresh)
+	r.Get("/auth/oidc/login", h.SSOLogin)
+	r.Get("/auth/oidc/callback", h.SSOCallback)
+
+	// Per-route permissions (see auth.RolePermissions and auth.APIKeyScopes)
+	readItinerary := Require(auth.PermItineraryRead)
+	readResources := Require(auth.PermResourcesRead)
+	writeBlocks := Require(auth.PermBlocksWrite)
+	writeMovements := Require(auth.PermMovementsWrite)
+	writeTemplates := Require(auth.PermTemplatesWrite)
+	writeEvents := Require(auth.PermEventsWrite)
+	writeResources := Require(auth.PermResourcesWrite)
+	manageAPIKeys := Require(auth.PermAPIKeysManage)
+	readAudit := Require(auth.PermAuditRead)
+
+	// Creates honour Idempotency-Key. Issuing API keys and access links is left out:
+	// their responses carry a secret that must not be stored.
+	idempotent := Idempotent(svcs, logger)
+
+	// Everything below requires a session and is scoped to the user's organization
+	r.Group(func(r chi.Router) {
+		r.Use(Authenticate(svcs))
+		r.Use(RequireOrganization())
+
+		r.Post("/auth/logout", h.Logout)
+		r.Get("/auth/me", h.Me)
+
+		// Organizations
+		r.Route("/organizations", func(r chi.Router) {
+			r.Get("/", h.ListOrganizations)
+			r.Get("/{orgId}", h.GetOrganization)
 		})
-	})
 
-	// Vehicles
-	r.Route("/vehicles", func(r chi.Router) {
-		r.Get("/", h.ListVehicles)
-		r.Post("/", h.CreateVehicle)
-		r.Route("/{id}", func(r chi.Router) {
-			r.Get("/", h.GetVehicle)
-			r.Put("/", h.UpdateVehicle)
-			r.Delete("/", h.DeleteVehicle)
+		// Locations
+		r.Route("/locations", func(r chi.Router) {
+			r.With(readResources).Get("/", h.ListLocations)
+			r.With(writeResources, idempotent).Post("/", h.CreateLocation)
+			r.Route("/{id}", func(r chi.Router) {
+				r.With(readResources).Get("/", h.GetLocation)
+				r.With(writeResources).Put("/", h.UpdateLocation)
+				r.With(writeResources).Patch("/", h.PatchLocation)
+				r.With(writeResources).Delete("/", h.DeleteLocation)
+			})
 		})
-	})
 
-	// Participants
-	r.Route("/participants", func(r chi.Router) {
-		r.Get("/", h.ListParticipants)
-		r.Post("/", h.CreateParticipant)
-		r.Route("/{id}", func(r chi.Router) {
-			r.Get("/", h.GetParticipant)
-			r.Put("/", h.UpdateParticipant)
-			r.Delete("/", h.DeleteParticipant)
+		// Vehicles
+		r.Route("/vehicles", func(r chi.Router) {
+			r.With(readResources).Get("/", h.ListVehicles)
+			r.With(writeResources, idempotent).Post("/", h.CreateVehicle)
+			r.Route("/{id}", func(r chi.Router) {
+				r.With(readResources).Get("/", h.GetVehicle)
+				r.With(writeResources).Put("/", h.UpdateVehicle)
+				r.With(writeResources).Patch("/", h.PatchVehicle)
+				r.With(writeResources).Delete("/", h.DeleteVehicle)
+			})
 		})
-	})
 
-	// Days
-	r.Route("/days", func(r chi.Router) {
-		r.Get("/", h.ListDays)
-		r.Post("/", h.CreateDays) // supports single or range
-		r.Route("/{dayId}", func(r chi.Router) {
-			r.Get("/", h.GetDay)
-			r.Delete("/", h.DeleteDay)
-			// Blocks
-			r.Route("/blocks", func(r chi.Router) {
-				r.Get("/", h.ListBlocks)
-				r.Post("/", h.CreateBlock)
-				r.Route("/{blockId}", func(r chi.Router) {
-					r.Get("/", h.GetBlock)
-					r.Put("/", h.UpdateBlock)
-					r.Delete("/", h.DeleteBlock)
-				})
+		// Participants
+		r.Route("/participants", func(r chi.Router) {
+			r.With(readResources).Get("/", h.ListParticipants)
+			r.With(writeResources, idempotent).Post("/", h.CreateParticipant)
+			r.Route("/{id}", func(r chi.Router) {
+				r.With(readResources).Get("/", h.GetParticipant)
+				r.With(writeResources).Put("/", h.UpdateParticipant)
+				r.With(writeResources).Patch("/", h.PatchParticipant)
+				r.With(writeResources).Delete("/", h.DeleteParticipant)
+				r.With(writeResources).Get("/access-tokens", h.ListParticipantTokens)
+				r.With(writeResources).Post("/access-tokens", h.IssueParticipantToken)
+				r.With(writeResources).Delete("/access-tokens/{tokenId}", h.RevokeParticipantToken)
+			})
+		})
+
+		// API keys
+		r.Route("/api-keys", func(r chi.Router) {
+			r.With(manageAPIKeys).Get("/", h.ListAPIKeys)
+			r.With(manageAPIKeys).Post("/", h.CreateAPIKey)
+			r.With(manageAPIKeys).Get("/{keyId}", h.GetAPIKey)
+			r.With(manageAPIKeys).Delete("/{keyId}", h.RevokeAPIKey)
+		})
+
+		// Audit log
+		r.With(readAudit).Get("/audit", h.ListAudit)
+
+		// Trash: permissions depend on the type of the deleted row and are checked by the handlers
+		r.Route("/trash", func(r chi.Router) {
+			r.Get("/", h.ListTrash)
+			r.Post("/{type}/{id}/restore", h.RestoreTrash)
+			r.Delete("/{type}/{id}", h.PurgeTrash)
+		})
+
+		// Block templates
+		r.Route("/block-templates", func(r chi.Router) {
+			r.With(readResources).Get("/", h.ListBlockTemplates)
+			r.With(writeTemplates, idempotent).Post("/", h.CreateBlockTemplate)
+			r.Route("/{templateId}", func(r chi.Router) {
+				r.With(readResources).Get("/", h.GetBlockTemplate)
+				r.With(writeTemplates).Put("/", h.UpdateBlockTemplate)
+				r.With(writeTemplates).Delete("/", h.DeleteBlockTemplate)
 			})
-			// Movements
-			r.Route("/movements", func(r chi.Router) {
-				r.Get("/", h.ListMovements)
-				r.Post("/", h.CreateMovement)
-				r.Route("/{movementId}", func(r chi.Router) {
-					r.Get("/", h.GetMovement)
-					r.Put("/", h.UpdateMovement)
-					r.Delete("/", h.DeleteMovement)
+		})
+
+		// Events and event-scoped views
+		r.Route("/events", func(r chi.Router) {
+			r.With(readItinerary).Get("/", h.ListEvents)
+			r.With(writeEvents, idempotent).Post("/", h.CreateEvent)
+			r.Route("/{eventId}", func(r chi.Router) {
+				r.With(readItinerary).Get("/", h.GetEvent)
+				r.With(writeEvents).Put("/", h.UpdateEvent)
+				r.With(writeEvents).Delete("/", h.DeleteEvent)
+				r.With(writeEvents, idempotent).Post("/clone", h.CloneEvent)
+				r.With(writeEvents).Post("/archive", h.ArchiveEvent)
+				r.With(writeEvents).Post("/unarchive", h.UnarchiveEvent)
+				r.With(writeEvents).Get("/members", h.ListEventMembers)
+				r.With(writeEvents).Put("/members/{userId}", h.PutEventMember)
+				r.With(writeEvents).Delete("/members/{userId}", h.DeleteEventMember)
+				r.With(readItinerary).Get("/days", h.ListDays)
+				r.With(writeEvents, idempotent).Post("/days", h.CreateDays)
+				r.With(readItinerary).Get("/itinerary", h.Itinerary)
+				r.With(readItinerary).Get("/export/pdf", h.ExportPDF)
+				r.With(readItinerary).Get("/export/ics", h.ExportICS)
+			})
+		})
+
+		// Days
+		r.Route("/days", func(r chi.Router) {
+			r.Route("/{dayId}", func(r chi.Router) {
+				r.With(readItinerary).Get("/", h.GetDay)
+				r.With(writeEvents).Delete("/", h.DeleteDay)
+				r.With(writeEvents, idempotent).Post("/duplicate", h.DuplicateDay)
+				// Blocks
+				r.Route("/blocks", func(r chi.Router) {
+					r.With(readItinerary).Get("/", h.ListBlocks)
+					r.With(writeBlocks, idempotent).Post("/", h.CreateBlock)
+					r.With(writeBlocks, idempotent).Post("/from-template", h.CreateBlockFromTemplate)
+					r.Route("/{blockId}", func(r chi.Router) {
+						r.With(readItinerary).Get("/", h.GetBlock)
+						r.With(writeBlocks).Put("/", h.UpdateBlock)
+						r.With(writeBlocks).Patch("/", h.PatchBlock)
+						r.With(writeBlocks).Delete("/", h.DeleteBlock)
+						r.With(writeTemplates, idempotent).Post("/template", h.SaveBlockAsTemplate)
+						r.With(readItinerary).Get("/revisions", h.ListBlockRevisions)
+						r.With(readItinerary).Get("/revisions/diff", h.DiffBlockRevisions)
+						r.With(readItinerary).Get("/revisions/{version}", h.GetBlockRevision)
+						r.With(writeBlocks).Post("/revisions/{version}/revert", h.RevertBlock)
+						r.Route("/schedule-items", func(r chi.Router) {
+							r.With(readItinerary).Get("/", h.ListScheduleItems)
+							r.With(writeBlocks, idempotent).Post("/", h.CreateScheduleItem)
+							r.Route("/{itemId}", func(r chi.Router) {
+								r.With(readItinerary).Get("/", h.GetScheduleItem)
+								r.With(writeBlocks).Put("/", h.UpdateScheduleItem)
+								r.With(writeBlocks).Delete("/", h.DeleteScheduleItem)
+								r.With(writeBlocks).Post("/move", h.MoveScheduleItem)
+							})
+						})
+					})
+				})
+				// Movements
+				r.Route("/movements", func(r chi.Router) {
+					r.With(readItinerary).Get("/", h.ListMovements)
+					r.With(writeMovements, idempotent).Post("/", h.CreateMovement)
+					r.Route("/{movementId}", func(r chi.Router) {
+						r.With(readItinerary).Get("/", h.GetMovement)
+						r.With(writeMovements).Put("/", h.UpdateMovement)
+						r.With(writeMovements).Patch("/", h.PatchMovement)
+						r.With(writeMovements).Delete("/", h.DeleteMovement)
+						r.With(readItinerary).Get("/revisions", h.ListMovementRevisions)
+						r.With(readItinerary).Get("/revisions/diff", h.DiffMovementRevisions)
+						r.With(readItinerary).Get("/revisions/{version}", h.GetMovementRevision)
+						r.With(writeMovements).Post("/revisions/{version}/revert", h.RevertMovement)
+					})
 				})
 			})
 		})
 	})
 
-	// Itinerary and Agenda
-	r.Get("/itinerary", h.Itinerary)
-	r.Get("/agenda/{participantId}", h.Agenda)
+	// Agenda: a session or a participant access token (magic link)
+	r.Group(func(r chi.Router) {
+		r.Use(AuthenticateAgenda(svcs))
+		r.Use(RequireOrganization())
 
-	// PDF export
-	r.Get("/export/pdf", h.ExportPDF)
+		r.With(RequireOwnAgenda).Get("/agenda/{participantId}", h.Agenda)
+		r.With(RequireOwnAgenda).Get("/agenda/{participantId}/export/ics", h.AgendaICS)
+	})
 
 	return r
 }
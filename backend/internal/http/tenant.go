@@ -0,0 +1,31 @@
+package http
+
+import (
+	"net/http"
+
+	"planning-system/backend/internal/tenant"
+	"planning-system/backend/pkg/respond"
+)
+
+// OrganizationHeader optionally names the organization (tenant) a request operates on.
+const OrganizationHeader = "X-Organization-ID"
+
+// RequireOrganization runs after Authenticate, which scopes the request to the
+// user's organization. A client may still send X-Organization-ID; it must then
+// match that organization.
+func RequireOrganization() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			orgID := tenant.OrganizationID(r.Context())
+			if orgID == "" {
+				respond.Error(w, http.StatusUnauthorized, "authentication required")
+				return
+			}
+			if h := r.Header.Get(OrganizationHeader); h != "" && h != orgID {
+				respond.Error(w, http.StatusForbidden, "organization not accessible")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -1,11 +1,198 @@
 package models
 
+import (
+	"encoding/json"
+	"time"
+)
+
+type Organization struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type User struct {
+	ID             string  `json:"id"`
+	OrganizationID string  `json:"organizationId"`
+	Email          string  `json:"email"`
+	Name           string  `json:"name,omitempty"`
+	Role           string  `json:"role"`                    // "admin" | "planner" | "staff" | "participant"
+	ParticipantID  *string `json:"participantId,omitempty"` // participant record of a participant user
+	PasswordHash   string  `json:"-"`
+}
+
+// EventMember gives a planner or staff user access to one event: "planner" may
+// change its schedule, "viewer" may only read it.
+type EventMember struct {
+	EventID   string    `json:"eventId"`
+	UserID    string    `json:"userId"`
+	Email     string    `json:"email,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	Role      string    `json:"role"` // "planner" | "viewer"
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type PutEventMemberRequest struct {
+	Role string `json:"role"`
+}
+
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+// AuthTokens is returned by login and refresh. Tokens are opaque; send the access
+// token as "Authorization: Bearer <token>".
+type AuthTokens struct {
+	AccessToken      string    `json:"accessToken"`
+	RefreshToken     string    `json:"refreshToken"`
+	TokenType        string    `json:"tokenType"` // "Bearer"
+	ExpiresAt        time.Time `json:"expiresAt"`
+	RefreshExpiresAt time.Time `json:"refreshExpiresAt"`
+	User             User      `json:"user"`
+}
+
+// ParticipantAccessToken describes an issued magic-link token; the token value
+// itself is only returned once, when it is issued.
+type ParticipantAccessToken struct {
+	ID             string     `json:"id"`
+	OrganizationID string     `json:"-"`
+	ParticipantID  string     `json:"participantId"`
+	Label          string     `json:"label,omitempty"`
+	ExpiresAt      time.Time  `json:"expiresAt"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	RevokedAt      *time.Time `json:"revokedAt,omitempty"`
+}
+
+type IssueAccessTokenRequest struct {
+	Label          string `json:"label,omitempty"`
+	ExpiresInHours int    `json:"expiresInHours,omitempty"` // default 720 (30 days)
+}
+
+type IssuedAccessToken struct {
+	Token       string                 `json:"token"`
+	AgendaPath  string                 `json:"agendaPath"` // e.g. /agenda/{participantId}?token=...
+	AccessToken ParticipantAccessToken `json:"accessToken"`
+}
+
+// APIKey is an integration credential with named scopes (see auth.APIKeyScopes).
+// The key itself is only returned once, when it is created.
+type APIKey struct {
+	ID             string     `json:"id"`
+	OrganizationID string     `json:"-"`
+	Name           string     `json:"name"`
+	Prefix         string     `json:"prefix"` // first characters of the key, to recognise it
+	Scopes         []string   `json:"scopes"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	ExpiresAt      *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt     *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt      *time.Time `json:"revokedAt,omitempty"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"` // never expires if omitted
+}
+
+type CreatedAPIKey struct {
+	Key    string `json:"key"`
+	APIKey APIKey `json:"apiKey"`
+}
+
+// AuditEntry records one change made through the API. Before is empty for
+// creations and After for deletions, except deletions that replaced or removed
+// references, where After lists them.
+type AuditEntry struct {
+	ID         string          `json:"id"`
+	Action     string          `json:"action"`     // "create" | "update" | "delete" | "duplicate" | "restore" | "purge" | "revert"
+	EntityType string          `json:"entityType"` // "location" | "vehicle" | "participant" | "day" | "block" | "movement"
+	EntityID   string          `json:"entityId"`
+	ActorType  string          `json:"actorType"` // "user" | "api_key"
+	ActorID    *string         `json:"actorId,omitempty"`
+	ActorName  string          `json:"actorName,omitempty"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
+
+// AuditFilter narrows the audit log listing; empty fields match everything.
+type AuditFilter struct {
+	EntityType string
+	EntityID   string
+	ActorID    string
+	Action     string
+	From       *time.Time
+	To         *time.Time
+}
+
+// Revision is a block or movement as it was at one of its versions, including its
+// schedule items or vehicle assignments. The actor is taken from the audit log entry
+// that produced the version, if there is one. CreatedAt is unset for a version that
+// was only recorded when the row was next changed, such as that of a row created before
+// revisions were kept or copied from another day.
+type Revision struct {
+	Version   int             `json:"version"`
+	ActorType string          `json:"actorType,omitempty"` // "user" | "api_key"
+	ActorID   *string         `json:"actorId,omitempty"`
+	ActorName string          `json:"actorName,omitempty"`
+	CreatedAt *time.Time      `json:"createdAt,omitempty"`
+	Snapshot  json.RawMessage `json:"snapshot"` // the block or movement
+}
+
+// RevisionDiff lists what changed between two revisions. Schedule items are matched
+// by id and vehicle assignments by vehicle, so their changes are reported per item,
+// e.g. "scheduleItems[<id>].time"; an added or removed item has a null From or To.
+type RevisionDiff struct {
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Changes []FieldChange `json:"changes"`
+}
+
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// Reference is a row that uses a location, vehicle or participant, reported when
+// deleting it. Field names the property holding the reference; EventID, DayID and
+// Date are set for blocks, movements and vehicle assignments, and MovementID for
+// vehicle assignments.
+type Reference struct {
+	Type       string `json:"type"` // "block" | "movement" | "vehicle_assignment" | "vehicle" | "block_template" | "user"
+	ID         string `json:"id"`
+	Label      string `json:"label"`
+	Field      string `json:"field"`
+	EventID    string `json:"eventId,omitempty"`
+	DayID      string `json:"dayId,omitempty"`
+	Date       string `json:"date,omitempty"`
+	MovementID string `json:"movementId,omitempty"`
+	ReadOnly   bool   `json:"readOnly,omitempty"` // in an archived event: never replaced or removed
+}
+
+// TrashItem is a deleted row that can still be restored or purged. EventID, DayID and
+// Date are set for days, blocks and movements.
+type TrashItem struct {
+	Type      string    `json:"type"` // "location" | "vehicle" | "participant" | "day" | "block" | "movement"
+	ID        string    `json:"id"`
+	Label     string    `json:"label"`
+	EventID   string    `json:"eventId,omitempty"`
+	DayID     string    `json:"dayId,omitempty"`
+	Date      string    `json:"date,omitempty"`
+	DeletedAt time.Time `json:"deletedAt"`
+}
+
 type Location struct {
 	ID             string `json:"id"`
 	Name           string `json:"name"`
 	Address        string `json:"address,omitempty"`
 	GoogleMapsLink string `json:"googleMapsLink,omitempty"`
 	Type           string `json:"type,omitempty"`
+	Version        int    `json:"version,omitempty"`
 }
 
 type Vehicle struct {
@@ -19,6 +206,7 @@ type Vehicle struct {
 	AvailableFrom        *string `json:"availableFrom,omitempty"`
 	AvailableTo          *string `json:"availableTo,omitempty"`
 	OriginationLocationID *string `json:"originationLocationId,omitempty"`
+	Version              int     `json:"version,omitempty"`
 }
 
 type Participant struct {
@@ -29,14 +217,17 @@ type Participant struct {
 	Phone           string   `json:"phone,omitempty"`
 	Languages       []string `json:"languages,omitempty"`
 	AssignedBlockIDs []string `json:"assignedBlockIds,omitempty"` // derived
+	Version         int      `json:"version,omitempty"`
 }
 
 type Event struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description,omitempty"`
-	StartDate   string `json:"startDate"` // ISO date (YYYY-MM-DD)
-	EndDate     string `json:"endDate"`   // ISO date (YYYY-MM-DD)
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	StartDate   string     `json:"startDate"`            // ISO date (YYYY-MM-DD)
+	EndDate     string     `json:"endDate"`              // ISO date (YYYY-MM-DD)
+	TimeZone    string     `json:"timeZone"`             // IANA name, e.g. "Europe/Istanbul"; defaults to "UTC"
+	ArchivedAt  *time.Time `json:"archivedAt,omitempty"` // set when archived (read-only)
 }
 
 type Day struct {
@@ -60,13 +251,15 @@ type AgendaItem struct {
 }
 
 type ScheduleItem struct {
-	ID                string  `json:"id"`
-	BlockID           string  `json:"-"` // internal only
-	Time              string  `json:"time"` // HH:mm
-	Description       string  `json:"description"`
-	StaffInstructions string  `json:"staffInstructions,omitempty"`
-	GuestInstructions string  `json:"guestInstructions,omitempty"`
-	Notes             *string `json:"notes,omitempty"`
+	ID                string     `json:"id"`
+	BlockID           string     `json:"-"`            // internal only
+	Time              string     `json:"time"`                // HH:mm
+	DayOffset         int        `json:"dayOffset,omitempty"` // days after the block's day (1 = after midnight)
+	At                *time.Time `json:"at,omitempty"`        // derived: absolute instant in the event's time zone
+	Description       string     `json:"description"`
+	StaffInstructions string     `json:"staffInstructions,omitempty"`
+	GuestInstructions string     `json:"guestInstructions,omitempty"`
+	Notes             *string    `json:"notes,omitempty"`
 }
 
 type Block struct {
@@ -77,6 +270,9 @@ type Block struct {
 	Description           string         `json:"description,omitempty"`
 	StartTime             string         `json:"startTime"` // HH:mm
 	EndTime               string         `json:"endTime,omitempty"` // HH:mm
+	EndDayOffset          int            `json:"endDayOffset,omitempty"` // days after the block's day the end falls on (1 = next day)
+	StartAt               *time.Time     `json:"startAt,omitempty"` // derived: absolute instant in the event's time zone
+	EndAt                 *time.Time     `json:"endAt,omitempty"`   // derived
 	EndTimeFixed          *bool          `json:"endTimeFixed,omitempty"`
 	LocationID            *string        `json:"locationId,omitempty"`
 	ParticipantsIds       []string        `json:"participantsIds,omitempty"`
@@ -85,6 +281,7 @@ type Block struct {
 	Attachments           []string        `json:"attachments,omitempty"`
 	Notes                 string          `json:"notes,omitempty"`
 	ScheduleItems         []ScheduleItem  `json:"scheduleItems,omitempty"` // ordered by time
+	Version               int             `json:"version,omitempty"` // incremented on every update; sent as the ETag
 }
 
 type Movement struct {
@@ -97,10 +294,14 @@ type Movement struct {
 	FromTime           string             `json:"fromTime"` // HH:mm
 	ToTimeType         string             `json:"toTimeType"` // "fixed" | "driving"
 	ToTime             string             `json:"toTime"` // HH:mm if fixed, or total minutes as string if driving
+	ToDayOffset        int                `json:"toDayOffset,omitempty"` // fixed only: days after the movement's day the arrival falls on
+	FromAt             *time.Time         `json:"fromAt,omitempty"` // derived: absolute departure in the event's time zone
+	ToAt               *time.Time         `json:"toAt,omitempty"`   // derived: absolute arrival
 	DrivingTimeHours   *int               `json:"drivingTimeHours,omitempty"`
 	DrivingTimeMinutes *int               `json:"drivingTimeMinutes,omitempty"`
 	VehicleAssignments []VehicleAssignment `json:"vehicleAssignments,omitempty"`
 	Notes              string             `json:"notes,omitempty"`
+	Version            int                `json:"version,omitempty"`
 }
 
 type VehicleAssignment struct {
@@ -116,3 +317,78 @@ type CreateDaysRequest struct {
 }
 
 
+
+type CloneEventRequest struct {
+	Name                   string `json:"name"`
+	Description            string `json:"description,omitempty"`
+	StartDate              string `json:"startDate"`                        // ISO date (YYYY-MM-DD) of the new event
+	TimeZone               string `json:"timeZone,omitempty"`               // defaults to the source event's time zone
+	DropParticipants       bool   `json:"dropParticipants,omitempty"`       // skip block participants, passengers and drivers
+	DropVehicleAssignments bool   `json:"dropVehicleAssignments,omitempty"` // skip vehicle assignments entirely
+}
+
+// CopyReport counts the rows copied by a clone or duplicate operation.
+type CopyReport struct {
+	Days               int64 `json:"days"`
+	Blocks             int64 `json:"blocks"`
+	ScheduleItems      int64 `json:"scheduleItems"`
+	Movements          int64 `json:"movements"`
+	VehicleAssignments int64 `json:"vehicleAssignments"`
+}
+
+type CloneEventResult struct {
+	Event  Event      `json:"event"`
+	Copied CopyReport `json:"copied"`
+}
+
+type DuplicateDayRequest struct {
+	TargetDayID            string `json:"targetDayId,omitempty"`            // existing day to copy into
+	Date                   string `json:"date,omitempty"`                   // or ISO date (YYYY-MM-DD) in the same event, created if missing
+	Mode                   string `json:"mode,omitempty"`                   // "merge" (default) | "replace"
+	DropParticipants       bool   `json:"dropParticipants,omitempty"`       // skip block participants, passengers and drivers
+	DropVehicleAssignments bool   `json:"dropVehicleAssignments,omitempty"` // skip vehicle assignments entirely
+}
+
+type DuplicateDayResult struct {
+	Day    Day        `json:"day"`
+	Copied CopyReport `json:"copied"`
+}
+
+// MoveScheduleItemRequest names the block a schedule item moves to. DayID defaults to
+// the day of the block the item is in.
+type MoveScheduleItemRequest struct {
+	DayID   string `json:"dayId,omitempty"`
+	BlockID string `json:"blockId"`
+}
+
+type BlockTemplate struct {
+	ID              string              `json:"id"`
+	Name            string              `json:"name"`
+	Type            string              `json:"type"` // "activity" | "break"
+	Title           string              `json:"title"`
+	Description     string              `json:"description,omitempty"`
+	DurationMinutes *int                `json:"durationMinutes,omitempty"`
+	EndTimeFixed    *bool               `json:"endTimeFixed,omitempty"`
+	LocationID      *string             `json:"locationId,omitempty"`
+	Notes           string              `json:"notes,omitempty"`
+	Items           []BlockTemplateItem `json:"items"` // ordered by offset
+}
+
+type BlockTemplateItem struct {
+	ID                string  `json:"id"`
+	TemplateID        string  `json:"-"`             // internal only
+	OffsetMinutes     int     `json:"offsetMinutes"` // minutes after the block start
+	Description       string  `json:"description"`
+	StaffInstructions string  `json:"staffInstructions,omitempty"`
+	GuestInstructions string  `json:"guestInstructions,omitempty"`
+	Notes             *string `json:"notes,omitempty"`
+}
+
+type SaveBlockTemplateRequest struct {
+	Name string `json:"name"`
+}
+
+type InstantiateBlockTemplateRequest struct {
+	TemplateID string `json:"templateId"`
+	StartTime  string `json:"startTime"` // HH:mm
+}
@@ -0,0 +1,95 @@
+package repos
+
+import (
+	"context"
+
+	"planning-system/backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type APIKeysRepo struct{ RepoBase }
+
+func NewAPIKeysRepo(pool *pgxpool.Pool) *APIKeysRepo {
+	return &APIKeysRepo{RepoBase{Pool: pool}}
+}
+
+const apiKeyColumns = `id, organization_id, name, key_prefix, scopes, created_at, expires_at, last_used_at, revoked_at`
+
+func scanAPIKey(row interface{ Scan(...any) error }, k *models.APIKey) error {
+	return row.Scan(&k.ID, &k.OrganizationID, &k.Name, &k.Prefix, &k.Scopes, &k.CreatedAt, &k.ExpiresAt, &k.LastUsedAt, &k.RevokedAt)
+}
+
+func (r *APIKeysRepo) List(ctx context.Context, orgID string) ([]models.APIKey, error) {
+	rows, err := r.Pool.Query(ctx, `
+		SELECT `+apiKeyColumns+`
+		FROM api_keys WHERE organization_id = $1
+		ORDER BY created_at DESC
+	`, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]models.APIKey, 0)
+	for rows.Next() {
+		var k models.APIKey
+		if err := scanAPIKey(rows, &k); err != nil {
+			return nil, err
+		}
+		items = append(items, k)
+	}
+	return items, rows.Err()
+}
+
+func (r *APIKeysRepo) Get(ctx context.Context, orgID, id string) (models.APIKey, error) {
+	var k models.APIKey
+	row := r.Pool.QueryRow(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE id = $1 AND organization_id = $2`, id, orgID)
+	err := scanOne(ctx, row, &k, func() error { return scanAPIKey(row, &k) })
+	return k, err
+}
+
+// GetActiveByHash returns the key with the given hash if it is neither revoked nor expired.
+func (r *APIKeysRepo) GetActiveByHash(ctx context.Context, keyHash string) (models.APIKey, error) {
+	var k models.APIKey
+	row := r.Pool.QueryRow(ctx, `
+		SELECT `+apiKeyColumns+`
+		FROM api_keys
+		WHERE key_hash = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > now())
+	`, keyHash)
+	err := scanOne(ctx, row, &k, func() error { return scanAPIKey(row, &k) })
+	return k, err
+}
+
+func (r *APIKeysRepo) Create(ctx context.Context, in models.APIKey, keyHash, createdBy string) (models.APIKey, error) {
+	if in.ID == "" {
+		in.ID = uuid.NewString()
+	}
+	err := r.Pool.QueryRow(ctx, `
+		INSERT INTO api_keys (id, organization_id, name, key_prefix, key_hash, scopes, expires_at, created_by)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,NULLIF($8,'')::uuid)
+		RETURNING created_at
+	`, in.ID, in.OrganizationID, in.Name, in.Prefix, keyHash, in.Scopes, in.ExpiresAt, createdBy).Scan(&in.CreatedAt)
+	return in, err
+}
+
+// TouchLastUsed records that the key was used. To avoid a write per request it
+// only updates keys not already marked within the last minute.
+func (r *APIKeysRepo) TouchLastUsed(ctx context.Context, id string) error {
+	_, err := r.Pool.Exec(ctx, `
+		UPDATE api_keys SET last_used_at = now()
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute')
+	`, id)
+	return err
+}
+
+// Revoke marks the key as revoked; revoking twice is a no-op.
+func (r *APIKeysRepo) Revoke(ctx context.Context, orgID, id string) (models.APIKey, error) {
+	var k models.APIKey
+	row := r.Pool.QueryRow(ctx, `
+		UPDATE api_keys SET revoked_at = COALESCE(revoked_at, now())
+		WHERE id = $1 AND organization_id = $2
+		RETURNING `+apiKeyColumns, id, orgID)
+	err := scanOne(ctx, row, &k, func() error { return scanAPIKey(row, &k) })
+	return k, err
+}
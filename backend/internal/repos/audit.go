@@ -0,0 +1,91 @@
+package repos
+
+import (
+	"context"
+	"strings"
+
+	"planning-system/backend/internal/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AuditRepo struct{ RepoBase }
+
+func NewAuditRepo(pool *pgxpool.Pool) *AuditRepo {
+	return &AuditRepo{RepoBase{Pool: pool}}
+}
+
+func (r *AuditRepo) Record(ctx context.Context, orgID string, e models.AuditEntry) error {
+	_, err := r.Pool.Exec(ctx, `
+		INSERT INTO audit_log (organization_id, actor_type, actor_id, actor_name, action, entity_type, entity_id, before, after)
+		VALUES ($1,$2,NULLIF($3,'')::uuid,$4,$5,$6,$7,$8,$9)
+	`, orgID, e.ActorType, nullableString(e.ActorID), e.ActorName, e.Action, e.EntityType, e.EntityID, nullableJSON(e.Before), nullableJSON(e.After))
+	return err
+}
+
+// List returns the matching entries, newest first, and their total count.
+func (r *AuditRepo) List(ctx context.Context, orgID string, f models.AuditFilter, p PageParams) ([]models.AuditEntry, int64, error) {
+	args := []any{orgID}
+	where := []string{"organization_id = $1"}
+	add := func(cond string, v any) {
+		args = append(args, v)
+		where = append(where, strings.ReplaceAll(cond, "?", "$"+itoa(len(args))))
+	}
+	if f.EntityType != "" {
+		add("entity_type = ?", f.EntityType)
+	}
+	if f.EntityID != "" {
+		add("entity_id = ?::uuid", f.EntityID)
+	}
+	if f.ActorID != "" {
+		add("actor_id = ?::uuid", f.ActorID)
+	}
+	if f.Action != "" {
+		add("action = ?", f.Action)
+	}
+	if f.From != nil {
+		add("created_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		add("created_at < ?", *f.To)
+	}
+	q := `
+		SELECT id, action, entity_type, entity_id::text, actor_type, actor_id::text, actor_name, before, after, created_at
+		FROM audit_log
+	`
+	q += " WHERE " + strings.Join(where, " AND ")
+	q += " ORDER BY created_at DESC LIMIT $" + itoa(len(args)+1) + " OFFSET $" + itoa(len(args)+2)
+	args = append(args, p.Limit, p.Offset)
+	rows, err := r.Pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	items := make([]models.AuditEntry, 0)
+	for rows.Next() {
+		var e models.AuditEntry
+		var before, after []byte
+		if err := rows.Scan(&e.ID, &e.Action, &e.EntityType, &e.EntityID, &e.ActorType, &e.ActorID, &e.ActorName, &before, &after, &e.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		e.Before, e.After = before, after
+		items = append(items, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	var total int64
+	countQ := "SELECT COUNT(*) FROM audit_log WHERE " + strings.Join(where, " AND ")
+	if err := r.Pool.QueryRow(ctx, countQ, args[:len(args)-2]...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// nullableJSON stores an empty snapshot as SQL NULL.
+func nullableJSON(b []byte) any {
+	if len(b) == 0 {
+		return nil
+	}
+	return string(b)
+}
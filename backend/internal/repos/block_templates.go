@@ -0,0 +1,210 @@
+package repos
+
+import (
+	"context"
+
+	"planning-system/backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type BlockTemplatesRepo struct{ RepoBase }
+
+func NewBlockTemplatesRepo(pool *pgxpool.Pool) *BlockTemplatesRepo {
+	return &BlockTemplatesRepo{RepoBase{Pool: pool}}
+}
+
+func (r *BlockTemplatesRepo) List(ctx context.Context, orgID string) ([]models.BlockTemplate, error) {
+	rows, err := r.Pool.Query(ctx, `
+		SELECT id, name, type, title, COALESCE(description,''), duration_minutes, end_time_fixed,
+		       location_id::text, COALESCE(notes,'')
+		FROM block_templates
+		WHERE organization_id = $1
+		ORDER BY name ASC
+	`, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]models.BlockTemplate, 0)
+	var ids []string
+	for rows.Next() {
+		var t models.BlockTemplate
+		var endTimeFixed bool
+		if err := rows.Scan(&t.ID, &t.Name, &t.Type, &t.Title, &t.Description, &t.DurationMinutes, &endTimeFixed, &t.LocationID, &t.Notes); err != nil {
+			return nil, err
+		}
+		t.EndTimeFixed = &endTimeFixed
+		items = append(items, t)
+		ids = append(ids, t.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return items, nil
+	}
+	byTemplate, err := r.listItems(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].Items = byTemplate[items[i].ID]
+		if items[i].Items == nil {
+			items[i].Items = []models.BlockTemplateItem{}
+		}
+	}
+	return items, nil
+}
+
+func (r *BlockTemplatesRepo) listItems(ctx context.Context, templateIDs []string) (map[string][]models.BlockTemplateItem, error) {
+	rows, err := r.Pool.Query(ctx, `
+		SELECT id, template_id, offset_minutes, description, COALESCE(staff_instructions,''), COALESCE(guest_instructions,''), notes
+		FROM block_template_items
+		WHERE template_id = ANY($1::uuid[])
+		ORDER BY template_id, offset_minutes ASC
+	`, templateIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := map[string][]models.BlockTemplateItem{}
+	for rows.Next() {
+		var it models.BlockTemplateItem
+		if err := rows.Scan(&it.ID, &it.TemplateID, &it.OffsetMinutes, &it.Description, &it.StaffInstructions, &it.GuestInstructions, &it.Notes); err != nil {
+			return nil, err
+		}
+		out[it.TemplateID] = append(out[it.TemplateID], it)
+	}
+	return out, rows.Err()
+}
+
+func (r *BlockTemplatesRepo) Get(ctx context.Context, orgID, id string) (models.BlockTemplate, error) {
+	var t models.BlockTemplate
+	var endTimeFixed bool
+	row := r.Pool.QueryRow(ctx, `
+		SELECT id, name, type, title, COALESCE(description,''), duration_minutes, end_time_fixed,
+		       location_id::text, COALESCE(notes,'')
+		FROM block_templates WHERE id = $1 AND organization_id = $2
+	`, id, orgID)
+	err := scanOne(ctx, row, &t, func() error {
+		return row.Scan(&t.ID, &t.Name, &t.Type, &t.Title, &t.Description, &t.DurationMinutes, &endTimeFixed, &t.LocationID, &t.Notes)
+	})
+	if err != nil {
+		return t, err
+	}
+	t.EndTimeFixed = &endTimeFixed
+	byTemplate, err := r.listItems(ctx, []string{t.ID})
+	if err != nil {
+		return t, err
+	}
+	t.Items = byTemplate[t.ID]
+	if t.Items == nil {
+		t.Items = []models.BlockTemplateItem{}
+	}
+	return t, nil
+}
+
+func (r *BlockTemplatesRepo) Create(ctx context.Context, orgID string, in models.BlockTemplate) (models.BlockTemplate, error) {
+	if in.ID == "" {
+		in.ID = uuid.NewString()
+	}
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return models.BlockTemplate{}, err
+	}
+	defer rollbackTx(tx)
+	var endTimeFixed bool
+	if in.EndTimeFixed != nil {
+		endTimeFixed = *in.EndTimeFixed
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO block_templates (id, organization_id, name, type, title, description, duration_minutes, end_time_fixed, location_id, notes)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,NULLIF($9,'')::uuid,$10)
+	`, in.ID, orgID, in.Name, in.Type, in.Title, in.Description, in.DurationMinutes, endTimeFixed, nullableString(in.LocationID), in.Notes)
+	if err != nil {
+		return models.BlockTemplate{}, err
+	}
+	if in.Items, err = insertTemplateItems(ctx, tx, in.ID, in.Items); err != nil {
+		return models.BlockTemplate{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return models.BlockTemplate{}, err
+	}
+	in.EndTimeFixed = &endTimeFixed
+	return in, nil
+}
+
+func (r *BlockTemplatesRepo) Update(ctx context.Context, orgID, id string, in models.BlockTemplate) (models.BlockTemplate, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return models.BlockTemplate{}, err
+	}
+	defer rollbackTx(tx)
+	var endTimeFixed bool
+	if in.EndTimeFixed != nil {
+		endTimeFixed = *in.EndTimeFixed
+	}
+	tag, err := tx.Exec(ctx, `
+		UPDATE block_templates
+		SET name=$2, type=$3, title=$4, description=$5, duration_minutes=$6, end_time_fixed=$7, location_id=NULLIF($8,'')::uuid, notes=$9
+		WHERE id=$1 AND organization_id=$10
+	`, id, in.Name, in.Type, in.Title, in.Description, in.DurationMinutes, endTimeFixed, nullableString(in.LocationID), in.Notes, orgID)
+	if err != nil {
+		return models.BlockTemplate{}, err
+	}
+	if tag.RowsAffected() == 0 {
+		return models.BlockTemplate{}, ErrNotFound
+	}
+	// items: replace
+	if _, err := tx.Exec(ctx, `DELETE FROM block_template_items WHERE template_id=$1`, id); err != nil {
+		return models.BlockTemplate{}, err
+	}
+	if in.Items, err = insertTemplateItems(ctx, tx, id, in.Items); err != nil {
+		return models.BlockTemplate{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return models.BlockTemplate{}, err
+	}
+	in.ID = id
+	in.EndTimeFixed = &endTimeFixed
+	return in, nil
+}
+
+func insertTemplateItems(ctx context.Context, tx pgx.Tx, templateID string, items []models.BlockTemplateItem) ([]models.BlockTemplateItem, error) {
+	out := make([]models.BlockTemplateItem, 0, len(items))
+	for _, it := range items {
+		it.ID = uuid.NewString()
+		it.TemplateID = templateID
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO block_template_items (id, template_id, offset_minutes, description, staff_instructions, guest_instructions, notes)
+			VALUES ($1,$2,$3,$4,$5,$6,$7)
+		`, it.ID, templateID, it.OffsetMinutes, it.Description, it.StaffInstructions, it.GuestInstructions, it.Notes); err != nil {
+			return nil, err
+		}
+		out = append(out, it)
+	}
+	return out, nil
+}
+
+// Delete removes the template and its items, or returns ErrNotFound.
+func (r *BlockTemplatesRepo) Delete(ctx context.Context, orgID, id string) error {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollbackTx(tx)
+	tag, err := tx.Exec(ctx, `DELETE FROM block_templates WHERE id::text=$1 AND organization_id=$2`, id, orgID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM block_template_items WHERE template_id=$1`, id); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -2,6 +2,7 @@ package repos
 
 import (
 	"context"
+	"errors"
 
 	"planning-system/backend/internal/models"
 
@@ -15,18 +16,18 @@ func NewBlocksRepo(pool *pgxpool.Pool) *BlocksRepo {
 	return &BlocksRepo{RepoBase{Pool: pool}}
 }
 
-func (r *BlocksRepo) ListByDay(ctx context.Context, dayID string) ([]models.Block, error) {
+func (r *BlocksRepo) ListByDay(ctx context.Context, orgID, dayID string) ([]models.Block, error) {
 	rows, err := r.Pool.Query(ctx, `
 		SELECT id, day_id, type, title, COALESCE(description,''), 
-		       to_char(start_time,'HH24:MI'), COALESCE(to_char(end_time,'HH24:MI'),''), end_time_fixed,
-		       location_id::text, COALESCE(notes,''),
+		       to_char(start_time,'HH24:MI'), COALESCE(to_char(end_time,'HH24:MI'),''), end_day_offset, end_time_fixed,
+		       location_id::text, COALESCE(notes,''), version,
 		       COALESCE((SELECT array_agg(participant_id::text) FROM block_participants bp WHERE bp.block_id=b.id), '{}') AS p1,
 		       COALESCE((SELECT array_agg(participant_id::text) FROM block_advance_participants bp WHERE bp.block_id=b.id), '{}') AS p2,
 		       COALESCE((SELECT array_agg(participant_id::text) FROM block_met_by_participants bp WHERE bp.block_id=b.id), '{}') AS p3
 		FROM blocks b
-		WHERE day_id = $1
-		ORDER BY start_time ASC
-	`, dayID)
+		WHERE day_id = $1 AND b.deleted_at IS NULL AND day_id IN (SELECT d.id FROM days d JOIN events e ON e.id = d.event_id WHERE e.organization_id = $2 AND d.deleted_at IS NULL)
+		ORDER BY start_time ASC, end_day_offset ASC, end_time ASC NULLS LAST
+	`, dayID, orgID)
 	if err != nil {
 		return nil, err
 	}
@@ -36,7 +37,7 @@ func (r *BlocksRepo) ListByDay(ctx context.Context, dayID string) ([]models.Bloc
 		var blk models.Block
 		var locationID *string
 		var endTimeFixed bool
-		if err := rows.Scan(&blk.ID, &blk.DayID, &blk.Type, &blk.Title, &blk.Description, &blk.StartTime, &blk.EndTime, &endTimeFixed, &locationID, &blk.Notes, &blk.ParticipantsIds, &blk.AdvanceParticipantIDs, &blk.MetByParticipantIDs); err != nil {
+		if err := rows.Scan(&blk.ID, &blk.DayID, &blk.Type, &blk.Title, &blk.Description, &blk.StartTime, &blk.EndTime, &blk.EndDayOffset, &endTimeFixed, &locationID, &blk.Notes, &blk.Version, &blk.ParticipantsIds, &blk.AdvanceParticipantIDs, &blk.MetByParticipantIDs); err != nil {
 			return nil, err
 		}
 		blk.EndTimeFixed = &endTimeFixed
@@ -46,10 +47,10 @@ func (r *BlocksRepo) ListByDay(ctx context.Context, dayID string) ([]models.Bloc
 	}
 	// load schedule items
 	itemRows, err := r.Pool.Query(ctx, `
-		SELECT id, block_id, to_char(time,'HH24:MI'), description, COALESCE(staff_instructions,''), COALESCE(guest_instructions,''), notes
+		SELECT id, block_id, to_char(time,'HH24:MI'), day_offset, description, COALESCE(staff_instructions,''), COALESCE(guest_instructions,''), notes
 		FROM schedule_items
 		WHERE block_id = ANY($1::uuid[])
-		ORDER BY time ASC
+		ORDER BY day_offset ASC, time ASC
 	`, collectBlockIDs(blocks))
 	if err != nil {
 		return nil, err
@@ -59,7 +60,7 @@ func (r *BlocksRepo) ListByDay(ctx context.Context, dayID string) ([]models.Bloc
 	for itemRows.Next() {
 		var it models.ScheduleItem
 		var notes *string
-		if err := itemRows.Scan(&it.ID, &it.BlockID, &it.Time, &it.Description, &it.StaffInstructions, &it.GuestInstructions, &notes); err != nil {
+		if err := itemRows.Scan(&it.ID, &it.BlockID, &it.Time, &it.DayOffset, &it.Description, &it.StaffInstructions, &it.GuestInstructions, &notes); err != nil {
 			return nil, err
 		}
 		it.Notes = notes
@@ -68,7 +69,13 @@ func (r *BlocksRepo) ListByDay(ctx context.Context, dayID string) ([]models.Bloc
 	for i := range blocks {
 		blocks[i].ScheduleItems = itemsByBlock[blocks[i].ID]
 	}
-	return blocks, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.localizeBlocks(ctx, blocks); err != nil {
+		return nil, err
+	}
+	return blocks, nil
 }
 
 func collectBlockIDs(blks []models.Block) []string {
@@ -80,21 +87,21 @@ func collectBlockIDs(blks []models.Block) []string {
 }
 
 // ListByDays fetches all blocks for multiple days in a single query
-func (r *BlocksRepo) ListByDays(ctx context.Context, dayIDs []string) ([]models.Block, error) {
+func (r *BlocksRepo) ListByDays(ctx context.Context, orgID string, dayIDs []string) ([]models.Block, error) {
 	if len(dayIDs) == 0 {
 		return []models.Block{}, nil
 	}
 	rows, err := r.Pool.Query(ctx, `
 		SELECT id, day_id, type, title, COALESCE(description,''), 
-		       to_char(start_time,'HH24:MI'), COALESCE(to_char(end_time,'HH24:MI'),''), end_time_fixed,
-		       location_id::text, COALESCE(notes,''),
+		       to_char(start_time,'HH24:MI'), COALESCE(to_char(end_time,'HH24:MI'),''), end_day_offset, end_time_fixed,
+		       location_id::text, COALESCE(notes,''), version,
 		       COALESCE((SELECT array_agg(participant_id::text) FROM block_participants bp WHERE bp.block_id=b.id), '{}') AS p1,
 		       COALESCE((SELECT array_agg(participant_id::text) FROM block_advance_participants bp WHERE bp.block_id=b.id), '{}') AS p2,
 		       COALESCE((SELECT array_agg(participant_id::text) FROM block_met_by_participants bp WHERE bp.block_id=b.id), '{}') AS p3
 		FROM blocks b
-		WHERE day_id = ANY($1::uuid[])
-		ORDER BY day_id, start_time ASC
-	`, dayIDs)
+		WHERE day_id = ANY($1::uuid[]) AND b.deleted_at IS NULL AND day_id IN (SELECT d.id FROM days d JOIN events e ON e.id = d.event_id WHERE e.organization_id = $2 AND d.deleted_at IS NULL)
+		ORDER BY day_id, start_time ASC, end_day_offset ASC, end_time ASC NULLS LAST
+	`, dayIDs, orgID)
 	if err != nil {
 		return nil, err
 	}
@@ -104,7 +111,7 @@ func (r *BlocksRepo) ListByDays(ctx context.Context, dayIDs []string) ([]models.
 		var blk models.Block
 		var locationID *string
 		var endTimeFixed bool
-		if err := rows.Scan(&blk.ID, &blk.DayID, &blk.Type, &blk.Title, &blk.Description, &blk.StartTime, &blk.EndTime, &endTimeFixed, &locationID, &blk.Notes, &blk.ParticipantsIds, &blk.AdvanceParticipantIDs, &blk.MetByParticipantIDs); err != nil {
+		if err := rows.Scan(&blk.ID, &blk.DayID, &blk.Type, &blk.Title, &blk.Description, &blk.StartTime, &blk.EndTime, &blk.EndDayOffset, &endTimeFixed, &locationID, &blk.Notes, &blk.Version, &blk.ParticipantsIds, &blk.AdvanceParticipantIDs, &blk.MetByParticipantIDs); err != nil {
 			return nil, err
 		}
 		blk.EndTimeFixed = &endTimeFixed
@@ -119,10 +126,10 @@ func (r *BlocksRepo) ListByDays(ctx context.Context, dayIDs []string) ([]models.
 	// Load schedule items for all blocks
 	if len(blocks) > 0 {
 		itemRows, err := r.Pool.Query(ctx, `
-			SELECT id, block_id, to_char(time,'HH24:MI'), description, COALESCE(staff_instructions,''), COALESCE(guest_instructions,''), notes
+			SELECT id, block_id, to_char(time,'HH24:MI'), day_offset, description, COALESCE(staff_instructions,''), COALESCE(guest_instructions,''), notes
 			FROM schedule_items
 			WHERE block_id = ANY($1::uuid[])
-			ORDER BY block_id, time ASC
+			ORDER BY block_id, day_offset ASC, time ASC
 		`, collectBlockIDs(blocks))
 		if err != nil {
 			return nil, err
@@ -132,7 +139,7 @@ func (r *BlocksRepo) ListByDays(ctx context.Context, dayIDs []string) ([]models.
 		for itemRows.Next() {
 			var it models.ScheduleItem
 			var notes *string
-			if err := itemRows.Scan(&it.ID, &it.BlockID, &it.Time, &it.Description, &it.StaffInstructions, &it.GuestInstructions, &notes); err != nil {
+			if err := itemRows.Scan(&it.ID, &it.BlockID, &it.Time, &it.DayOffset, &it.Description, &it.StaffInstructions, &it.GuestInstructions, &notes); err != nil {
 				return nil, err
 			}
 			it.Notes = notes
@@ -148,12 +155,14 @@ func (r *BlocksRepo) ListByDays(ctx context.Context, dayIDs []string) ([]models.
 			}
 		}
 	}
-	
+	if err := r.localizeBlocks(ctx, blocks); err != nil {
+		return nil, err
+	}
 	return blocks, nil
 }
 
-func (r *BlocksRepo) Get(ctx context.Context, dayID, id string) (models.Block, error) {
-	list, err := r.ListByDay(ctx, dayID)
+func (r *BlocksRepo) Get(ctx context.Context, orgID, dayID, id string) (models.Block, error) {
+	list, err := r.ListByDay(ctx, orgID, dayID)
 	if err != nil {
 		return models.Block{}, err
 	}
@@ -168,7 +177,7 @@ func (r *BlocksRepo) Get(ctx context.Context, dayID, id string) (models.Block, e
 	return models.Block{}, ErrNotFound
 }
 
-func (r *BlocksRepo) Create(ctx context.Context, in models.Block) (models.Block, error) {
+func (r *BlocksRepo) Create(ctx context.Context, orgID string, in models.Block) (models.Block, error) {
 	if in.ID == "" {
 		in.ID = uuid.NewString()
 	}
@@ -177,14 +186,17 @@ func (r *BlocksRepo) Create(ctx context.Context, in models.Block) (models.Block,
 		return models.Block{}, err
 	}
 	defer rollbackTx(tx)
+	if err := dayInOrganization(ctx, tx, orgID, in.DayID); err != nil {
+		return models.Block{}, err
+	}
 	var endTimeFixed bool
 	if in.EndTimeFixed != nil {
 		endTimeFixed = *in.EndTimeFixed
 	}
 	_, err = tx.Exec(ctx, `
-		INSERT INTO blocks (id, day_id, type, title, description, start_time, end_time, end_time_fixed, location_id, notes)
-		VALUES ($1,$2,$3,$4,$5,$6::time, NULLIF($7,'')::time, $8, NULLIF($9,'')::uuid, $10)
-	`, in.ID, in.DayID, in.Type, in.Title, in.Description, in.StartTime, in.EndTime, endTimeFixed, nullableString(in.LocationID), in.Notes)
+		INSERT INTO blocks (id, day_id, type, title, description, start_time, end_time, end_time_fixed, location_id, notes, end_day_offset)
+		VALUES ($1,$2,$3,$4,$5,$6::time, NULLIF($7,'')::time, $8, NULLIF($9,'')::uuid, $10, $11)
+	`, in.ID, in.DayID, in.Type, in.Title, in.Description, in.StartTime, in.EndTime, endTimeFixed, nullableString(in.LocationID), in.Notes, in.EndDayOffset)
 	if err != nil {
 		return models.Block{}, err
 	}
@@ -209,27 +221,53 @@ func (r *BlocksRepo) Create(ctx context.Context, in models.Block) (models.Block,
 			}
 		}
 	}
-	// schedule items
-	for _, si := range in.ScheduleItems {
-		siID := si.ID
-		if siID == "" {
-			siID = uuid.NewString()
-		}
+	// schedule items: all new
+	for i := range in.ScheduleItems {
+		si := &in.ScheduleItems[i]
+		si.ID, si.BlockID = uuid.NewString(), in.ID
 		_, err := tx.Exec(ctx, `
-			INSERT INTO schedule_items (id, block_id, time, description, staff_instructions, guest_instructions, notes)
-			VALUES ($1,$2,$3::time,$4,$5,$6,$7)
-		`, siID, in.ID, si.Time, si.Description, si.StaffInstructions, si.GuestInstructions, si.Notes)
+			INSERT INTO schedule_items (id, block_id, time, description, staff_instructions, guest_instructions, notes, day_offset)
+			VALUES ($1,$2,$3::time,$4,$5,$6,$7,$8)
+		`, si.ID, in.ID, si.Time, si.Description, si.StaffInstructions, si.GuestInstructions, si.Notes, si.DayOffset)
 		if err != nil {
 			return models.Block{}, err
 		}
 	}
+	if err := blockRevisions.record(ctx, tx, in.ID, true); err != nil {
+		return models.Block{}, err
+	}
 	if err := tx.Commit(ctx); err != nil {
 		return models.Block{}, err
 	}
+	in.Version = 1
+	if err := r.localizeBlock(ctx, &in); err != nil {
+		return models.Block{}, err
+	}
 	return in, nil
 }
 
-func (r *BlocksRepo) Update(ctx context.Context, id string, in models.Block) (models.Block, error) {
+// BlockRelations selects the lists of a block that an update replaces.
+type BlockRelations struct {
+	Participants        bool
+	AdvanceParticipants bool
+	MetByParticipants   bool
+	ScheduleItems       bool
+}
+
+// AllBlockRelations replaces every list of the block.
+var AllBlockRelations = BlockRelations{Participants: true, AdvanceParticipants: true, MetByParticipants: true, ScheduleItems: true}
+
+// Update replaces the block with its participants and schedule items. If in.Version is
+// set, the update only applies to that version of the row and fails with
+// ErrVersionMismatch otherwise. The block as it was and as it becomes are kept as revisions.
+func (r *BlocksRepo) Update(ctx context.Context, orgID, id string, in models.Block) (models.Block, error) {
+	return r.Patch(ctx, orgID, id, in, AllBlockRelations)
+}
+
+// Patch is Update for a partial change: the block's own fields are all written, but only
+// the lists selected by rel are replaced and the others are left as stored. The returned
+// block carries the lists of in.
+func (r *BlocksRepo) Patch(ctx context.Context, orgID, id string, in models.Block, rel BlockRelations) (models.Block, error) {
 	tx, err := r.Pool.Begin(ctx)
 	if err != nil {
 		return models.Block{}, err
@@ -239,67 +277,96 @@ func (r *BlocksRepo) Update(ctx context.Context, id string, in models.Block) (mo
 	if in.EndTimeFixed != nil {
 		endTimeFixed = *in.EndTimeFixed
 	}
-	tag, err := tx.Exec(ctx, `
-		UPDATE blocks
-		SET type=$2, title=$3, description=$4, start_time=$5::time, end_time=NULLIF($6,'')::time, end_time_fixed=$7, location_id=NULLIF($8,'')::uuid, notes=$9
-		WHERE id=$1
-	`, id, in.Type, in.Title, in.Description, in.StartTime, in.EndTime, endTimeFixed, nullableString(in.LocationID), in.Notes)
-	if err != nil {
+	if err := blockRevisions.record(ctx, tx, id, false); err != nil {
 		return models.Block{}, err
 	}
-	if tag.RowsAffected() == 0 {
-		return models.Block{}, ErrNotFound
-	}
-	// reset relations
-	if _, err := tx.Exec(ctx, `DELETE FROM block_participants WHERE block_id=$1`, id); err != nil {
-		return models.Block{}, err
-	}
-	if _, err := tx.Exec(ctx, `DELETE FROM block_advance_participants WHERE block_id=$1`, id); err != nil {
-		return models.Block{}, err
-	}
-	if _, err := tx.Exec(ctx, `DELETE FROM block_met_by_participants WHERE block_id=$1`, id); err != nil {
+	row := tx.QueryRow(ctx, `
+		UPDATE blocks
+		SET type=$2, title=$3, description=$4, start_time=$5::time, end_time=NULLIF($6,'')::time, end_time_fixed=$7, location_id=NULLIF($8,'')::uuid, notes=$9, end_day_offset=$11, version=version+1
+		WHERE id=$1 AND day_id IN (SELECT d.id FROM days d JOIN events e ON e.id = d.event_id WHERE e.organization_id = $10 AND d.deleted_at IS NULL)
+		  AND deleted_at IS NULL AND ($12 = 0 OR version = $12)
+		RETURNING day_id::text, version
+	`, id, in.Type, in.Title, in.Description, in.StartTime, in.EndTime, endTimeFixed, nullableString(in.LocationID), in.Notes, orgID, in.EndDayOffset, in.Version)
+	if err := scanOne(ctx, row, &in.DayID, func() error { return row.Scan(&in.DayID, &in.Version) }); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			err = missedUpdate(ctx, tx, in.Version, `SELECT EXISTS (SELECT 1 FROM blocks WHERE id=$1 AND deleted_at IS NULL AND day_id IN (SELECT d.id FROM days d JOIN events e ON e.id = d.event_id WHERE e.organization_id = $2 AND d.deleted_at IS NULL))`, id, orgID)
+		}
 		return models.Block{}, err
 	}
-	for _, pid := range in.ParticipantsIds {
-		if _, err := tx.Exec(ctx, `INSERT INTO block_participants (block_id, participant_id) VALUES ($1,$2)`, id, pid); err != nil {
-			return models.Block{}, err
+	// reset relations
+	lists := []struct {
+		replace bool
+		table   string
+		ids     []string
+	}{
+		{rel.Participants, "block_participants", in.ParticipantsIds},
+		{rel.AdvanceParticipants, "block_advance_participants", in.AdvanceParticipantIDs},
+		{rel.MetByParticipants, "block_met_by_participants", in.MetByParticipantIDs},
+	}
+	for _, l := range lists {
+		if !l.replace {
+			continue
 		}
-	}
-	for _, pid := range in.AdvanceParticipantIDs {
-		if _, err := tx.Exec(ctx, `INSERT INTO block_advance_participants (block_id, participant_id) VALUES ($1,$2)`, id, pid); err != nil {
+		if _, err := tx.Exec(ctx, `DELETE FROM `+l.table+` WHERE block_id=$1`, id); err != nil {
 			return models.Block{}, err
 		}
-	}
-	for _, pid := range in.MetByParticipantIDs {
-		if _, err := tx.Exec(ctx, `INSERT INTO block_met_by_participants (block_id, participant_id) VALUES ($1,$2)`, id, pid); err != nil {
-			return models.Block{}, err
+		for _, pid := range l.ids {
+			if _, err := tx.Exec(ctx, `INSERT INTO `+l.table+` (block_id, participant_id) VALUES ($1,$2)`, id, pid); err != nil {
+				return models.Block{}, err
+			}
 		}
 	}
-	// schedule items: replace
-	if _, err := tx.Exec(ctx, `DELETE FROM schedule_items WHERE block_id=$1`, id); err != nil {
-		return models.Block{}, err
-	}
-	for _, si := range in.ScheduleItems {
-		siID := si.ID
-		if siID == "" {
-			siID = uuid.NewString()
+	// schedule items: items with an ID are updated in place, items without one are
+	// added, and items of the block left out of the list are deleted.
+	if rel.ScheduleItems {
+		keep := make([]string, 0, len(in.ScheduleItems))
+		for i := range in.ScheduleItems {
+			si := &in.ScheduleItems[i]
+			if si.ID == "" {
+				si.ID = uuid.NewString()
+			}
+			si.BlockID = id
+			keep = append(keep, si.ID)
 		}
-		if _, err := tx.Exec(ctx, `
-			INSERT INTO schedule_items (id, block_id, time, description, staff_instructions, guest_instructions, notes)
-			VALUES ($1,$2,$3::time,$4,$5,$6,$7)
-		`, siID, id, si.Time, si.Description, si.StaffInstructions, si.GuestInstructions, si.Notes); err != nil {
+		if _, err := tx.Exec(ctx, `DELETE FROM schedule_items WHERE block_id=$1 AND NOT (id = ANY($2::uuid[]))`, id, keep); err != nil {
 			return models.Block{}, err
 		}
+		for _, si := range in.ScheduleItems {
+			tag, err := tx.Exec(ctx, `
+				INSERT INTO schedule_items (id, block_id, time, description, staff_instructions, guest_instructions, notes, day_offset)
+				VALUES ($1,$2,$3::time,$4,$5,$6,$7,$8)
+				ON CONFLICT (id) DO UPDATE
+				SET time=EXCLUDED.time, description=EXCLUDED.description, staff_instructions=EXCLUDED.staff_instructions,
+				    guest_instructions=EXCLUDED.guest_instructions, notes=EXCLUDED.notes, day_offset=EXCLUDED.day_offset
+				WHERE schedule_items.block_id = EXCLUDED.block_id
+			`, si.ID, id, si.Time, si.Description, si.StaffInstructions, si.GuestInstructions, si.Notes, si.DayOffset)
+			if err != nil {
+				return models.Block{}, err
+			}
+			if tag.RowsAffected() == 0 {
+				return models.Block{}, ErrForeignScheduleItem
+			}
+		}
+	}
+	if err := blockRevisions.record(ctx, tx, id, true); err != nil {
+		return models.Block{}, err
 	}
 	if err := tx.Commit(ctx); err != nil {
 		return models.Block{}, err
 	}
 	in.ID = id
+	if err := r.localizeBlock(ctx, &in); err != nil {
+		return models.Block{}, err
+	}
 	return in, nil
 }
 
-func (r *BlocksRepo) Delete(ctx context.Context, id string) error {
-	_, err := r.Pool.Exec(ctx, `DELETE FROM blocks WHERE id=$1`, id)
+// Delete moves the block to the trash; its schedule items and participants stay with it.
+func (r *BlocksRepo) Delete(ctx context.Context, orgID, id string) error {
+	_, err := r.Pool.Exec(ctx, `
+		UPDATE blocks SET deleted_at=now()
+		WHERE id=$1 AND deleted_at IS NULL AND day_id IN (SELECT d.id FROM days d JOIN events e ON e.id = d.event_id WHERE e.organization_id = $2 AND d.deleted_at IS NULL)
+	`, id, orgID)
 	return err
 }
 
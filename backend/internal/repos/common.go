@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -20,6 +21,19 @@ func NewBase(pool *pgxpool.Pool) RepoBase {
 
 var ErrNotFound = errors.New("not found")
 
+// ErrVersionMismatch is returned by an update that expected a version the row no longer has.
+var ErrVersionMismatch = errors.New("version mismatch")
+
+// ForeignKeyViolation reports whether err is a foreign key violation and returns the
+// name of the violated constraint (see migration 0016_foreign_keys).
+func ForeignKeyViolation(err error) (string, bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+		return pgErr.ConstraintName, true
+	}
+	return "", false
+}
+
 type PageParams struct {
 	Limit  int
 	Offset int
@@ -60,6 +74,61 @@ func rollbackTx(tx pgx.Tx) {
 	_ = tx.Rollback(bgCtx)
 }
 
+// querier is satisfied by both the pool and a transaction.
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// dayInOrganization returns ErrNotFound unless the day belongs to an event of the organization.
+func dayInOrganization(ctx context.Context, q querier, orgID, dayID string) error {
+	var ok bool
+	err := q.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM days d JOIN events e ON e.id = d.event_id
+			WHERE d.id = $1 AND e.organization_id = $2 AND d.deleted_at IS NULL
+		)
+	`, dayID, orgID).Scan(&ok)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// missedUpdate explains a versioned update that matched no row: ErrVersionMismatch if the
+// row exists (exists is a SELECT EXISTS query for it), ErrNotFound otherwise.
+func missedUpdate(ctx context.Context, q querier, expected int, exists string, args ...any) error {
+	if expected == 0 {
+		return ErrNotFound
+	}
+	var ok bool
+	if err := q.QueryRow(ctx, exists, args...).Scan(&ok); err != nil {
+		return err
+	}
+	if ok {
+		return ErrVersionMismatch
+	}
+	return ErrNotFound
+}
+
+// missingIDs returns the ids that are not rows of the organization-owned table, counting
+// rows in the trash as missing.
+func missingIDs(ctx context.Context, pool *pgxpool.Pool, table, orgID string, ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	rows, err := pool.Query(ctx, `
+		SELECT x FROM unnest($1::text[]) x
+		WHERE NOT EXISTS (SELECT 1 FROM `+table+` t WHERE t.id::text = lower(x) AND t.organization_id = $2 AND t.deleted_at IS NULL)
+	`, ids, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
 // queryWithTimeout adds a timeout to a context for database queries
 func queryWithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
 	return context.WithTimeout(ctx, timeout)
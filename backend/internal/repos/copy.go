@@ -0,0 +1,117 @@
+package repos
+
+import (
+	"context"
+
+	"planning-system/backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// CopyOptions controls which relations are carried over when day contents are copied.
+type CopyOptions struct {
+	DropParticipants       bool
+	DropVehicleAssignments bool
+}
+
+// createDayMapping creates the temporary copy_days (old_id, new_id) table used by
+// copyDayContents. It is dropped automatically when the transaction ends.
+func createDayMapping(ctx context.Context, tx pgx.Tx) error {
+	_, err := tx.Exec(ctx, `CREATE TEMP TABLE copy_days (old_id UUID NOT NULL, new_id UUID NOT NULL) ON COMMIT DROP`)
+	return err
+}
+
+// copyDayContents copies blocks (with participant relations and schedule items) and
+// movements (with vehicle assignments and passengers) from every copy_days.old_id
+// to its copy_days.new_id. New IDs are generated for every copied row; rows in the
+// trash are not copied.
+func copyDayContents(ctx context.Context, tx pgx.Tx, opts CopyOptions) (models.CopyReport, error) {
+	var rep models.CopyReport
+
+	// Blocks
+	if _, err := tx.Exec(ctx, `
+		CREATE TEMP TABLE copy_blocks ON COMMIT DROP AS
+		SELECT b.id AS old_id, gen_random_uuid() AS new_id, cd.new_id AS day_id
+		FROM blocks b JOIN copy_days cd ON cd.old_id = b.day_id
+		WHERE b.deleted_at IS NULL
+	`); err != nil {
+		return rep, err
+	}
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO blocks (id, day_id, type, title, description, start_time, end_time, end_day_offset, end_time_fixed, location_id, notes)
+		SELECT cb.new_id, cb.day_id, b.type, b.title, b.description, b.start_time, b.end_time, b.end_day_offset, b.end_time_fixed, b.location_id, b.notes
+		FROM blocks b JOIN copy_blocks cb ON cb.old_id = b.id
+	`)
+	if err != nil {
+		return rep, err
+	}
+	rep.Blocks = tag.RowsAffected()
+	if !opts.DropParticipants {
+		for _, table := range []string{"block_participants", "block_advance_participants", "block_met_by_participants"} {
+			if _, err := tx.Exec(ctx, `
+				INSERT INTO `+table+` (block_id, participant_id)
+				SELECT cb.new_id, bp.participant_id
+				FROM `+table+` bp JOIN copy_blocks cb ON cb.old_id = bp.block_id
+			`); err != nil {
+				return rep, err
+			}
+		}
+	}
+	tag, err = tx.Exec(ctx, `
+		INSERT INTO schedule_items (id, block_id, time, day_offset, description, staff_instructions, guest_instructions, notes)
+		SELECT gen_random_uuid(), cb.new_id, si.time, si.day_offset, si.description, si.staff_instructions, si.guest_instructions, si.notes
+		FROM schedule_items si JOIN copy_blocks cb ON cb.old_id = si.block_id
+	`)
+	if err != nil {
+		return rep, err
+	}
+	rep.ScheduleItems = tag.RowsAffected()
+
+	// Movements
+	if _, err := tx.Exec(ctx, `
+		CREATE TEMP TABLE copy_movements ON COMMIT DROP AS
+		SELECT m.id AS old_id, gen_random_uuid() AS new_id, cd.new_id AS day_id
+		FROM movements m JOIN copy_days cd ON cd.old_id = m.day_id
+		WHERE m.deleted_at IS NULL
+	`); err != nil {
+		return rep, err
+	}
+	tag, err = tx.Exec(ctx, `
+		INSERT INTO movements (id, day_id, title, description, from_location_id, to_location_id, from_time, to_time_type, to_time, to_day_offset, driving_minutes)
+		SELECT cm.new_id, cm.day_id, m.title, m.description, m.from_location_id, m.to_location_id, m.from_time, m.to_time_type, m.to_time, m.to_day_offset, m.driving_minutes
+		FROM movements m JOIN copy_movements cm ON cm.old_id = m.id
+	`)
+	if err != nil {
+		return rep, err
+	}
+	rep.Movements = tag.RowsAffected()
+	if opts.DropVehicleAssignments {
+		return rep, nil
+	}
+	if _, err := tx.Exec(ctx, `
+		CREATE TEMP TABLE copy_assignments ON COMMIT DROP AS
+		SELECT va.id AS old_id, gen_random_uuid() AS new_id, cm.new_id AS movement_id
+		FROM vehicle_assignments va JOIN copy_movements cm ON cm.old_id = va.movement_id
+	`); err != nil {
+		return rep, err
+	}
+	tag, err = tx.Exec(ctx, `
+		INSERT INTO vehicle_assignments (id, movement_id, vehicle_id, driver_id)
+		SELECT ca.new_id, ca.movement_id, va.vehicle_id, CASE WHEN $1::boolean THEN NULL ELSE va.driver_id END
+		FROM vehicle_assignments va JOIN copy_assignments ca ON ca.old_id = va.id
+	`, opts.DropParticipants)
+	if err != nil {
+		return rep, err
+	}
+	rep.VehicleAssignments = tag.RowsAffected()
+	if !opts.DropParticipants {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO vehicle_assignment_passengers (assignment_id, participant_id)
+			SELECT ca.new_id, vp.participant_id
+			FROM vehicle_assignment_passengers vp JOIN copy_assignments ca ON ca.old_id = vp.assignment_id
+		`); err != nil {
+			return rep, err
+		}
+	}
+	return rep, nil
+}
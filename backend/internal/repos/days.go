@@ -2,30 +2,37 @@ package repos
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"planning-system/backend/internal/models"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type DaysRepo struct{ RepoBase }
 
+var ErrSameDay = errors.New("source and target day are the same")
+
 func NewDaysRepo(pool *pgxpool.Pool) *DaysRepo {
 	return &DaysRepo{RepoBase{Pool: pool}}
 }
 
-func (r *DaysRepo) List(ctx context.Context) ([]models.Day, error) {
+// List returns the days of a single event with their blocks and movements.
+func (r *DaysRepo) List(ctx context.Context, orgID, eventID string) ([]models.Day, error) {
 	// Add query timeout
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	rows, err := r.Pool.Query(ctx, `
-		SELECT id, event_id, to_char(date, 'YYYY-MM-DD') as date
-		FROM days
-		ORDER BY date ASC
-	`)
+		SELECT d.id, d.event_id, to_char(d.date, 'YYYY-MM-DD') as date
+		FROM days d
+		JOIN events e ON e.id = d.event_id
+		WHERE d.event_id = $1 AND e.organization_id = $2 AND d.deleted_at IS NULL
+		ORDER BY d.date ASC
+	`, eventID, orgID)
 	if err != nil {
 		return nil, err
 	}
@@ -56,13 +63,13 @@ func (r *DaysRepo) List(ctx context.Context) ([]models.Day, error) {
 	movementsRepo := NewMovementsRepo(r.Pool)
 	
 	// Fetch all blocks for all days in one go
-	allBlocks, err := blocksRepo.ListByDays(ctx, dayIDs)
+	allBlocks, err := blocksRepo.ListByDays(ctx, orgID, dayIDs)
 	if err != nil {
 		return nil, err
 	}
 	
 	// Fetch all movements for all days in one go
-	allMovements, err := movementsRepo.ListByDays(ctx, dayIDs)
+	allMovements, err := movementsRepo.ListByDays(ctx, orgID, dayIDs)
 	if err != nil {
 		return nil, err
 	}
@@ -91,20 +98,19 @@ func (r *DaysRepo) List(ctx context.Context) ([]models.Day, error) {
 	return items, nil
 }
 
-func (r *DaysRepo) Get(ctx context.Context, id string) (models.Day, error) {
+func (r *DaysRepo) Get(ctx context.Context, orgID, id string) (models.Day, error) {
 	// Add query timeout
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	var m models.Day
-	err := scanOne(ctx, r.Pool.QueryRow(ctx, `
-		SELECT id, event_id, to_char(date, 'YYYY-MM-DD')
-		FROM days WHERE id = $1
-	`, id), &m, func() error {
-		return r.Pool.QueryRow(ctx, `
-			SELECT id, event_id, to_char(date, 'YYYY-MM-DD')
-			FROM days WHERE id = $1
-		`, id).Scan(&m.ID, &m.EventID, &m.Date)
+	row := r.Pool.QueryRow(ctx, `
+		SELECT d.id, d.event_id, to_char(d.date, 'YYYY-MM-DD')
+		FROM days d JOIN events e ON e.id = d.event_id
+		WHERE d.id = $1 AND e.organization_id = $2 AND d.deleted_at IS NULL
+	`, id, orgID)
+	err := scanOne(ctx, row, &m, func() error {
+		return row.Scan(&m.ID, &m.EventID, &m.Date)
 	})
 	if err != nil {
 		return m, err
@@ -112,11 +118,11 @@ func (r *DaysRepo) Get(ctx context.Context, id string) (models.Day, error) {
 	// Fetch blocks and movements for this day
 	blocksRepo := NewBlocksRepo(r.Pool)
 	movementsRepo := NewMovementsRepo(r.Pool)
-	blocks, err := blocksRepo.ListByDay(ctx, m.ID)
+	blocks, err := blocksRepo.ListByDay(ctx, orgID, m.ID)
 	if err != nil {
 		return m, err
 	}
-	movements, err := movementsRepo.ListByDay(ctx, m.ID)
+	movements, err := movementsRepo.ListByDay(ctx, orgID, m.ID)
 	if err != nil {
 		return m, err
 	}
@@ -125,7 +131,7 @@ func (r *DaysRepo) Get(ctx context.Context, id string) (models.Day, error) {
 	return m, nil
 }
 
-func (r *DaysRepo) CreateRange(ctx context.Context, eventID string, fromDate, toDate string) ([]models.Day, error) {
+func (r *DaysRepo) CreateRange(ctx context.Context, orgID, eventID string, fromDate, toDate string) ([]models.Day, error) {
 	// parse dates
 	start, err := time.Parse("2006-01-02", fromDate)
 	if err != nil {
@@ -144,13 +150,21 @@ func (r *DaysRepo) CreateRange(ctx context.Context, eventID string, fromDate, to
 	}
 	defer rollbackTx(tx)
 
+	var owned bool
+	if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM events WHERE id=$1 AND organization_id=$2)`, eventID, orgID).Scan(&owned); err != nil {
+		return nil, err
+	}
+	if !owned {
+		return nil, ErrNotFound
+	}
+
 	var created []models.Day
 	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
 		id := uuid.NewString()
 		_, err := tx.Exec(ctx, `
 			INSERT INTO days (id, event_id, date)
 			VALUES ($1,$2,$3)
-			ON CONFLICT (event_id, date) DO NOTHING
+			ON CONFLICT (event_id, date) WHERE deleted_at IS NULL DO NOTHING
 		`, id, eventID, d)
 		if err != nil {
 			return nil, err
@@ -159,7 +173,7 @@ func (r *DaysRepo) CreateRange(ctx context.Context, eventID string, fromDate, to
 		var out models.Day
 		err = tx.QueryRow(ctx, `
 			SELECT id, event_id, to_char(date,'YYYY-MM-DD')
-			FROM days WHERE event_id=$1 AND date=$2
+			FROM days WHERE event_id=$1 AND date=$2 AND deleted_at IS NULL
 		`, eventID, d).Scan(&out.ID, &out.EventID, &out.Date)
 		if err != nil {
 			return nil, err
@@ -175,9 +189,109 @@ func (r *DaysRepo) CreateRange(ctx context.Context, eventID string, fromDate, to
 	return created, nil
 }
 
-func (r *DaysRepo) Delete(ctx context.Context, id string) error {
-	_, err := r.Pool.Exec(ctx, `DELETE FROM days WHERE id=$1`, id)
+// Delete moves the day to the trash. Its blocks and movements are left untouched and
+// are hidden with it; purging the day removes them through ON DELETE CASCADE.
+func (r *DaysRepo) Delete(ctx context.Context, orgID, id string) error {
+	_, err := r.Pool.Exec(ctx, `
+		UPDATE days d SET deleted_at=now()
+		FROM events e
+		WHERE d.id=$1 AND d.deleted_at IS NULL AND e.id = d.event_id AND e.organization_id=$2
+	`, id, orgID)
 	return err
 }
 
+// Duplicate copies all blocks and movements of a day into another day, either an
+// existing one (in.TargetDayID) or the day at in.Date in the same event, which is
+// created if needed. In "replace" mode the target day's program is removed first.
+func (r *DaysRepo) Duplicate(ctx context.Context, orgID, id string, in models.DuplicateDayRequest) (models.DuplicateDayResult, error) {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return models.DuplicateDayResult{}, err
+	}
+	defer rollbackTx(tx)
 
+	var eventID string
+	if err := tx.QueryRow(ctx, `
+		SELECT d.event_id FROM days d JOIN events e ON e.id = d.event_id
+		WHERE d.id=$1 AND e.organization_id=$2 AND d.deleted_at IS NULL
+	`, id, orgID).Scan(&eventID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.DuplicateDayResult{}, ErrNotFound
+		}
+		return models.DuplicateDayResult{}, err
+	}
+
+	var report models.CopyReport
+	targetID := in.TargetDayID
+	if targetID != "" {
+		if err := tx.QueryRow(ctx, `
+			SELECT d.id FROM days d JOIN events e ON e.id = d.event_id
+			WHERE d.id=$1 AND e.organization_id=$2 AND d.deleted_at IS NULL
+		`, targetID, orgID).Scan(&targetID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return models.DuplicateDayResult{}, ErrNotFound
+			}
+			return models.DuplicateDayResult{}, err
+		}
+	} else {
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO days (id, event_id, date)
+			VALUES ($1,$2,$3::date)
+			ON CONFLICT (event_id, date) WHERE deleted_at IS NULL DO NOTHING
+		`, uuid.NewString(), eventID, in.Date)
+		if err != nil {
+			return models.DuplicateDayResult{}, err
+		}
+		report.Days = tag.RowsAffected()
+		if err := tx.QueryRow(ctx, `
+			SELECT id FROM days WHERE event_id=$1 AND date=$2::date AND deleted_at IS NULL
+		`, eventID, in.Date).Scan(&targetID); err != nil {
+			return models.DuplicateDayResult{}, err
+		}
+	}
+	if targetID == id {
+		return models.DuplicateDayResult{}, ErrSameDay
+	}
+
+	if in.Mode == "replace" {
+		if err := clearDay(ctx, tx, targetID); err != nil {
+			return models.DuplicateDayResult{}, err
+		}
+	}
+	if err := createDayMapping(ctx, tx); err != nil {
+		return models.DuplicateDayResult{}, err
+	}
+	if _, err := tx.Exec(ctx, `INSERT INTO copy_days (old_id, new_id) VALUES ($1,$2)`, id, targetID); err != nil {
+		return models.DuplicateDayResult{}, err
+	}
+	copied, err := copyDayContents(ctx, tx, CopyOptions{
+		DropParticipants:       in.DropParticipants,
+		DropVehicleAssignments: in.DropVehicleAssignments,
+	})
+	if err != nil {
+		return models.DuplicateDayResult{}, err
+	}
+	copied.Days = report.Days
+	if err := tx.Commit(ctx); err != nil {
+		return models.DuplicateDayResult{}, err
+	}
+	day, err := r.Get(ctx, orgID, targetID)
+	if err != nil {
+		return models.DuplicateDayResult{}, err
+	}
+	return models.DuplicateDayResult{Day: day, Copied: copied}, nil
+}
+
+// clearDay moves every block and movement of a day to the trash.
+func clearDay(ctx context.Context, tx pgx.Tx, dayID string) error {
+	stmts := []string{
+		`UPDATE blocks SET deleted_at=now() WHERE day_id=$1 AND deleted_at IS NULL`,
+		`UPDATE movements SET deleted_at=now() WHERE day_id=$1 AND deleted_at IS NULL`,
+	}
+	for _, q := range stmts {
+		if _, err := tx.Exec(ctx, q, dayID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
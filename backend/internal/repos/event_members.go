@@ -0,0 +1,69 @@
+package repos
+
+import (
+	"context"
+
+	"planning-system/backend/internal/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type EventMembersRepo struct{ RepoBase }
+
+func NewEventMembersRepo(pool *pgxpool.Pool) *EventMembersRepo {
+	return &EventMembersRepo{RepoBase{Pool: pool}}
+}
+
+func (r *EventMembersRepo) List(ctx context.Context, orgID, eventID string) ([]models.EventMember, error) {
+	rows, err := r.Pool.Query(ctx, `
+		SELECT m.event_id, m.user_id, u.email, u.name, m.role, m.created_at
+		FROM event_members m
+		JOIN events e ON e.id = m.event_id AND e.organization_id = $2
+		JOIN users u ON u.id = m.user_id
+		WHERE m.event_id = $1
+		ORDER BY u.email ASC
+	`, eventID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]models.EventMember, 0)
+	for rows.Next() {
+		var m models.EventMember
+		if err := rows.Scan(&m.EventID, &m.UserID, &m.Email, &m.Name, &m.Role, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, m)
+	}
+	return items, rows.Err()
+}
+
+// Role returns the user's role on the event, or ErrNotFound if they are not a member.
+func (r *EventMembersRepo) Role(ctx context.Context, eventID, userID string) (string, error) {
+	var role string
+	row := r.Pool.QueryRow(ctx, `SELECT role FROM event_members WHERE event_id = $1 AND user_id = $2`, eventID, userID)
+	err := scanOne(ctx, row, &role, func() error { return row.Scan(&role) })
+	return role, err
+}
+
+// Put adds the user to the event or changes their role.
+func (r *EventMembersRepo) Put(ctx context.Context, eventID, userID, role string) (models.EventMember, error) {
+	m := models.EventMember{EventID: eventID, UserID: userID, Role: role}
+	err := r.Pool.QueryRow(ctx, `
+		INSERT INTO event_members (event_id, user_id, role) VALUES ($1,$2,$3)
+		ON CONFLICT (event_id, user_id) DO UPDATE SET role = EXCLUDED.role
+		RETURNING created_at
+	`, eventID, userID, role).Scan(&m.CreatedAt)
+	return m, err
+}
+
+func (r *EventMembersRepo) Delete(ctx context.Context, eventID, userID string) error {
+	tag, err := r.Pool.Exec(ctx, `DELETE FROM event_members WHERE event_id = $1 AND user_id = $2`, eventID, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package repos
+
+import (
+	"context"
+	"time"
+
+	"planning-system/backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type EventsRepo struct{ RepoBase }
+
+func NewEventsRepo(pool *pgxpool.Pool) *EventsRepo {
+	return &EventsRepo{RepoBase{Pool: pool}}
+}
+
+// List returns the organization's events; archived events are only included on request.
+// If memberUserID is set, only events that user is a member of are returned.
+func (r *EventsRepo) List(ctx context.Context, orgID string, includeArchived bool, memberUserID string) ([]models.Event, error) {
+	rows, err := r.Pool.Query(ctx, `
+		SELECT id, name, COALESCE(description,''), to_char(start_date,'YYYY-MM-DD'), to_char(end_date,'YYYY-MM-DD'), time_zone, archived_at
+		FROM events
+		WHERE organization_id = $1 AND ($2 OR archived_at IS NULL)
+		  AND ($3 = '' OR id IN (SELECT event_id FROM event_members WHERE user_id = NULLIF($3,'')::uuid))
+		ORDER BY start_date ASC, name ASC
+	`, orgID, includeArchived, memberUserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]models.Event, 0)
+	for rows.Next() {
+		var m models.Event
+		if err := rows.Scan(&m.ID, &m.Name, &m.Description, &m.StartDate, &m.EndDate, &m.TimeZone, &m.ArchivedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, m)
+	}
+	return items, rows.Err()
+}
+
+func (r *EventsRepo) Get(ctx context.Context, orgID, id string) (models.Event, error) {
+	var m models.Event
+	row := r.Pool.QueryRow(ctx, `
+		SELECT id, name, COALESCE(description,''), to_char(start_date,'YYYY-MM-DD'), to_char(end_date,'YYYY-MM-DD'), time_zone, archived_at
+		FROM events WHERE id = $1 AND organization_id = $2
+	`, id, orgID)
+	err := scanOne(ctx, row, &m, func() error {
+		return row.Scan(&m.ID, &m.Name, &m.Description, &m.StartDate, &m.EndDate, &m.TimeZone, &m.ArchivedAt)
+	})
+	return m, err
+}
+
+// GetByDay returns the event owning the given day.
+func (r *EventsRepo) GetByDay(ctx context.Context, orgID, dayID string) (models.Event, error) {
+	var eventID string
+	row := r.Pool.QueryRow(ctx, `SELECT event_id FROM days WHERE id = $1 AND deleted_at IS NULL`, dayID)
+	if err := scanOne(ctx, row, &eventID, func() error { return row.Scan(&eventID) }); err != nil {
+		return models.Event{}, err
+	}
+	return r.Get(ctx, orgID, eventID)
+}
+
+// SetArchived archives or restores an event and returns its new state.
+func (r *EventsRepo) SetArchived(ctx context.Context, orgID, id string, archived bool) (models.Event, error) {
+	tag, err := r.Pool.Exec(ctx, `
+		UPDATE events
+		SET archived_at = CASE WHEN $3 THEN COALESCE(archived_at, now()) ELSE NULL END
+		WHERE id=$1 AND organization_id=$2
+	`, id, orgID, archived)
+	if err != nil {
+		return models.Event{}, err
+	}
+	if tag.RowsAffected() == 0 {
+		return models.Event{}, ErrNotFound
+	}
+	return r.Get(ctx, orgID, id)
+}
+
+func (r *EventsRepo) Create(ctx context.Context, orgID string, in models.Event) (models.Event, error) {
+	if in.ID == "" {
+		in.ID = uuid.NewString()
+	}
+	_, err := r.Pool.Exec(ctx, `
+		INSERT INTO events (id, organization_id, name, description, start_date, end_date, time_zone)
+		VALUES ($1,$2,$3,$4,$5::date,$6::date,$7)
+	`, in.ID, orgID, in.Name, in.Description, in.StartDate, in.EndDate, in.TimeZone)
+	return in, err
+}
+
+func (r *EventsRepo) Update(ctx context.Context, orgID, id string, in models.Event) (models.Event, error) {
+	tag, err := r.Pool.Exec(ctx, `
+		UPDATE events
+		SET name=$3, description=$4, start_date=$5::date, end_date=$6::date, time_zone=$7
+		WHERE id=$1 AND organization_id=$2
+	`, id, orgID, in.Name, in.Description, in.StartDate, in.EndDate, in.TimeZone)
+	if err != nil {
+		return models.Event{}, err
+	}
+	if tag.RowsAffected() == 0 {
+		return models.Event{}, ErrNotFound
+	}
+	in.ID = id
+	return in, nil
+}
+
+// eventChildren delete, innermost first, everything an event ($1) contains, so that
+// nothing is left behind whether or not the foreign keys cascade.
+var eventChildren = []string{
+	`DELETE FROM schedule_items WHERE block_id IN (SELECT b.id FROM blocks b JOIN days d ON d.id = b.day_id WHERE d.event_id = $1)`,
+	`DELETE FROM block_participants WHERE block_id IN (SELECT b.id FROM blocks b JOIN days d ON d.id = b.day_id WHERE d.event_id = $1)`,
+	`DELETE FROM block_advance_participants WHERE block_id IN (SELECT b.id FROM blocks b JOIN days d ON d.id = b.day_id WHERE d.event_id = $1)`,
+	`DELETE FROM block_met_by_participants WHERE block_id IN (SELECT b.id FROM blocks b JOIN days d ON d.id = b.day_id WHERE d.event_id = $1)`,
+	`DELETE FROM blocks WHERE day_id IN (SELECT id FROM days WHERE event_id = $1)`,
+	`DELETE FROM vehicle_assignment_passengers WHERE assignment_id IN (
+		SELECT va.id FROM vehicle_assignments va JOIN movements m ON m.id = va.movement_id JOIN days d ON d.id = m.day_id WHERE d.event_id = $1
+	)`,
+	`DELETE FROM vehicle_assignments WHERE movement_id IN (SELECT m.id FROM movements m JOIN days d ON d.id = m.day_id WHERE d.event_id = $1)`,
+	`DELETE FROM movements WHERE day_id IN (SELECT id FROM days WHERE event_id = $1)`,
+	`DELETE FROM days WHERE event_id = $1`,
+	`DELETE FROM event_members WHERE event_id = $1`,
+}
+
+// Delete removes the event together with its days and everything on them, or
+// returns ErrNotFound.
+func (r *EventsRepo) Delete(ctx context.Context, orgID, id string) error {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollbackTx(tx)
+	var eventID string
+	row := tx.QueryRow(ctx, `SELECT id::text FROM events WHERE id::text=$1 AND organization_id=$2 FOR UPDATE`, id, orgID)
+	if err := scanOne(ctx, row, &eventID, func() error { return row.Scan(&eventID) }); err != nil {
+		return err
+	}
+	for _, q := range eventChildren {
+		if _, err := tx.Exec(ctx, q, eventID); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM events WHERE id=$1`, eventID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// Clone copies the event with all its days, blocks, schedule items, movements and
+// vehicle assignments into a new event starting at in.StartDate. Every date is
+// shifted by the offset between the old and the new start date.
+func (r *EventsRepo) Clone(ctx context.Context, orgID, id string, in models.CloneEventRequest) (models.CloneEventResult, error) {
+	src, err := r.Get(ctx, orgID, id)
+	if err != nil {
+		return models.CloneEventResult{}, err
+	}
+	oldStart, err := time.Parse("2006-01-02", src.StartDate)
+	if err != nil {
+		return models.CloneEventResult{}, err
+	}
+	newStart, err := time.Parse("2006-01-02", in.StartDate)
+	if err != nil {
+		return models.CloneEventResult{}, err
+	}
+	offset := int(newStart.Sub(oldStart).Hours() / 24)
+
+	out := models.Event{
+		ID:          uuid.NewString(),
+		Name:        in.Name,
+		Description: in.Description,
+		TimeZone:    in.TimeZone,
+	}
+	if out.Description == "" {
+		out.Description = src.Description
+	}
+	if out.TimeZone == "" {
+		out.TimeZone = src.TimeZone
+	}
+
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return models.CloneEventResult{}, err
+	}
+	defer rollbackTx(tx)
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO events (id, organization_id, name, description, start_date, end_date, time_zone)
+		SELECT $2, organization_id, $3, $4, start_date + $5::int, end_date + $5::int, $6
+		FROM events WHERE id = $1
+		RETURNING to_char(start_date,'YYYY-MM-DD'), to_char(end_date,'YYYY-MM-DD')
+	`, id, out.ID, out.Name, out.Description, offset, out.TimeZone).Scan(&out.StartDate, &out.EndDate); err != nil {
+		return models.CloneEventResult{}, err
+	}
+	if err := createDayMapping(ctx, tx); err != nil {
+		return models.CloneEventResult{}, err
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO copy_days (old_id, new_id)
+		SELECT id, gen_random_uuid() FROM days WHERE event_id = $1 AND deleted_at IS NULL
+	`, id); err != nil {
+		return models.CloneEventResult{}, err
+	}
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO days (id, event_id, date)
+		SELECT cd.new_id, $2, d.date + $3::int
+		FROM days d JOIN copy_days cd ON cd.old_id = d.id
+		WHERE d.event_id = $1
+	`, id, out.ID, offset)
+	if err != nil {
+		return models.CloneEventResult{}, err
+	}
+	report, err := copyDayContents(ctx, tx, CopyOptions{
+		DropParticipants:       in.DropParticipants,
+		DropVehicleAssignments: in.DropVehicleAssignments,
+	})
+	if err != nil {
+		return models.CloneEventResult{}, err
+	}
+	report.Days = tag.RowsAffected()
+	if err := tx.Commit(ctx); err != nil {
+		return models.CloneEventResult{}, err
+	}
+	return models.CloneEventResult{Event: out, Copied: report}, nil
+}
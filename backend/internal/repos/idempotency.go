@@ -0,0 +1,92 @@
+package repos
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type IdempotencyRepo struct{ RepoBase }
+
+func NewIdempotencyRepo(pool *pgxpool.Pool) *IdempotencyRepo {
+	return &IdempotencyRepo{RepoBase{Pool: pool}}
+}
+
+// StoredResponse is the response recorded for an idempotency key. StatusCode is 0
+// while the first request with the key is still being processed.
+type StoredResponse struct {
+	RequestHash string
+	StatusCode  int
+	ContentType string
+	ETag        string
+	Body        []byte
+}
+
+// Claim reserves the key of the actor for a request with the given hash. It returns
+// true if the key was free and the caller should process the request, or false and
+// what is stored for the key. Keys older than ttl are forgotten.
+func (r *IdempotencyRepo) Claim(ctx context.Context, orgID, actor, key, requestHash string, ttl time.Duration) (StoredResponse, bool, error) {
+	if _, err := r.Pool.Exec(ctx, `
+		DELETE FROM idempotency_keys
+		WHERE organization_id=$1 AND actor=$2 AND created_at < now() - make_interval(secs => $3)
+	`, orgID, actor, ttl.Seconds()); err != nil {
+		return StoredResponse{}, false, err
+	}
+	tag, err := r.Pool.Exec(ctx, `
+		INSERT INTO idempotency_keys (organization_id, actor, key, request_hash)
+		VALUES ($1,$2,$3,$4)
+		ON CONFLICT (organization_id, actor, key) DO NOTHING
+	`, orgID, actor, key, requestHash)
+	if err != nil {
+		return StoredResponse{}, false, err
+	}
+	if tag.RowsAffected() == 1 {
+		return StoredResponse{}, true, nil
+	}
+	var s StoredResponse
+	var status *int
+	var contentType, etag *string
+	err = r.Pool.QueryRow(ctx, `
+		SELECT request_hash, status_code, content_type, etag, response_body
+		FROM idempotency_keys WHERE organization_id=$1 AND actor=$2 AND key=$3
+	`, orgID, actor, key).Scan(&s.RequestHash, &status, &contentType, &etag, &s.Body)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Released by the first request in the meantime; the client may retry.
+			return StoredResponse{}, false, ErrNotFound
+		}
+		return StoredResponse{}, false, err
+	}
+	if status != nil {
+		s.StatusCode = *status
+	}
+	if contentType != nil {
+		s.ContentType = *contentType
+	}
+	if etag != nil {
+		s.ETag = *etag
+	}
+	return s, false, nil
+}
+
+// Complete stores the response to the request that claimed the key.
+func (r *IdempotencyRepo) Complete(ctx context.Context, orgID, actor, key string, resp StoredResponse) error {
+	_, err := r.Pool.Exec(ctx, `
+		UPDATE idempotency_keys
+		SET status_code=$4, content_type=NULLIF($5,''), etag=NULLIF($6,''), response_body=$7
+		WHERE organization_id=$1 AND actor=$2 AND key=$3
+	`, orgID, actor, key, resp.StatusCode, resp.ContentType, resp.ETag, resp.Body)
+	return err
+}
+
+// Release frees a claimed key whose request failed, so that it can be retried.
+func (r *IdempotencyRepo) Release(ctx context.Context, orgID, actor, key string) error {
+	_, err := r.Pool.Exec(ctx, `
+		DELETE FROM idempotency_keys
+		WHERE organization_id=$1 AND actor=$2 AND key=$3 AND status_code IS NULL
+	`, orgID, actor, key)
+	return err
+}
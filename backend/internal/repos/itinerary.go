@@ -15,15 +15,19 @@ func NewItineraryRepo(pool *pgxpool.Pool) *ItineraryRepo {
 	return &ItineraryRepo{RepoBase{Pool: pool}}
 }
 
-func (r *ItineraryRepo) Itinerary(ctx context.Context) ([]models.ItineraryDay, error) {
+// Itinerary returns the per-day blocks and movements of a single event.
+func (r *ItineraryRepo) Itinerary(ctx context.Context, orgID, eventID string) ([]models.ItineraryDay, error) {
 	// Add query timeout
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	// list days
 	rows, err := r.Pool.Query(ctx, `
-		SELECT id, event_id, to_char(date,'YYYY-MM-DD') FROM days ORDER BY date ASC
-	`)
+		SELECT d.id, d.event_id, to_char(d.date,'YYYY-MM-DD')
+		FROM days d JOIN events e ON e.id = d.event_id
+		WHERE d.event_id = $1 AND e.organization_id = $2 AND d.deleted_at IS NULL
+		ORDER BY d.date ASC
+	`, eventID, orgID)
 	if err != nil {
 		return nil, err
 	}
@@ -50,12 +54,12 @@ func (r *ItineraryRepo) Itinerary(ctx context.Context) ([]models.ItineraryDay, e
 	blocksRepo := NewBlocksRepo(r.Pool)
 	movementsRepo := NewMovementsRepo(r.Pool)
 	
-	allBlocks, err := blocksRepo.ListByDays(ctx, dayIDs)
+	allBlocks, err := blocksRepo.ListByDays(ctx, orgID, dayIDs)
 	if err != nil {
 		return nil, err
 	}
 	
-	allMovements, err := movementsRepo.ListByDays(ctx, dayIDs)
+	allMovements, err := movementsRepo.ListByDays(ctx, orgID, dayIDs)
 	if err != nil {
 		return nil, err
 	}
@@ -91,7 +95,9 @@ func (r *ItineraryRepo) Itinerary(ctx context.Context) ([]models.ItineraryDay, e
 	return out, nil
 }
 
-func (r *ItineraryRepo) Agenda(ctx context.Context, participantID string) ([]models.AgendaItem, error) {
+// Agenda lists the participant's blocks across events; archived events are skipped unless includeArchived is set.
+// If memberUserID is set, only events that user is a member of are included.
+func (r *ItineraryRepo) Agenda(ctx context.Context, orgID, participantID string, includeArchived bool, memberUserID string) ([]models.AgendaItem, error) {
 	// Add query timeout
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
@@ -99,13 +105,17 @@ func (r *ItineraryRepo) Agenda(ctx context.Context, participantID string) ([]mod
 	rows, err := r.Pool.Query(ctx, `
 		SELECT d.id, to_char(d.date,'YYYY-MM-DD'),
 		       b.id, b.day_id, b.type, b.title, COALESCE(b.description,''), 
-		       to_char(b.start_time,'HH24:MI'), COALESCE(to_char(b.end_time,'HH24:MI'),''), b.end_time_fixed,
-		       b.location_id::text, COALESCE(b.notes,'')
+		       to_char(b.start_time,'HH24:MI'), COALESCE(to_char(b.end_time,'HH24:MI'),''), b.end_day_offset, b.end_time_fixed,
+		       b.location_id::text, COALESCE(b.notes,''), e.time_zone
 		FROM blocks b
 		JOIN days d ON d.id = b.day_id
+		JOIN events e ON e.id = d.event_id AND e.organization_id = $2 AND ($3 OR e.archived_at IS NULL)
 		JOIN block_participants bp ON bp.block_id = b.id AND bp.participant_id = $1
-		ORDER BY d.date ASC, b.start_time ASC
-	`, participantID)
+		JOIN participants p ON p.id = bp.participant_id AND p.deleted_at IS NULL
+		WHERE b.deleted_at IS NULL AND d.deleted_at IS NULL
+		  AND ($4 = '' OR e.id IN (SELECT event_id FROM event_members WHERE user_id = NULLIF($4,'')::uuid))
+		ORDER BY (d.date + b.start_time) AT TIME ZONE e.time_zone ASC
+	`, participantID, orgID, includeArchived, memberUserID)
 	if err != nil {
 		return nil, err
 	}
@@ -116,12 +126,15 @@ func (r *ItineraryRepo) Agenda(ctx context.Context, participantID string) ([]mod
 		var blk models.Block
 		var locationID *string
 		var endTimeFixed bool
-		if err := rows.Scan(&di.DayID, &di.Date, &blk.ID, &blk.DayID, &blk.Type, &blk.Title, &blk.Description, &blk.StartTime, &blk.EndTime, &endTimeFixed, &locationID, &blk.Notes); err != nil {
+		var timeZone string
+		if err := rows.Scan(&di.DayID, &di.Date, &blk.ID, &blk.DayID, &blk.Type, &blk.Title, &blk.Description, &blk.StartTime, &blk.EndTime, &blk.EndDayOffset, &endTimeFixed, &locationID, &blk.Notes, &timeZone); err != nil {
 			return nil, err
 		}
 		blk.EndTimeFixed = &endTimeFixed
 		blk.LocationID = locationID
 		blk.Attachments = []string{} // ensure it's initialized
+		// Each block is resolved in its own event's time zone.
+		dayClock{Date: di.Date, Loc: LoadLocation(timeZone)}.setBlockTimes(&blk)
 		di.Block = blk
 		items = append(items, di)
 	}
@@ -2,6 +2,7 @@ package repos
 
 import (
 	"context"
+	"errors"
 
 	"planning-system/backend/internal/models"
 
@@ -15,12 +16,13 @@ func NewLocationsRepo(pool *pgxpool.Pool) *LocationsRepo {
 	return &LocationsRepo{RepoBase{Pool: pool}}
 }
 
-func (r *LocationsRepo) List(ctx context.Context) ([]models.Location, error) {
+func (r *LocationsRepo) List(ctx context.Context, orgID string) ([]models.Location, error) {
 	rows, err := r.Pool.Query(ctx, `
-		SELECT id, name, COALESCE(address,''), COALESCE(google_maps_link,''), COALESCE(type,'')
+		SELECT id, name, COALESCE(address,''), COALESCE(google_maps_link,''), COALESCE(type,''), version
 		FROM locations
+		WHERE organization_id = $1 AND deleted_at IS NULL
 		ORDER BY name ASC
-	`)
+	`, orgID)
 	if err != nil {
 		return nil, err
 	}
@@ -28,7 +30,7 @@ func (r *LocationsRepo) List(ctx context.Context) ([]models.Location, error) {
 	var items []models.Location
 	for rows.Next() {
 		var m models.Location
-		if err := rows.Scan(&m.ID, &m.Name, &m.Address, &m.GoogleMapsLink, &m.Type); err != nil {
+		if err := rows.Scan(&m.ID, &m.Name, &m.Address, &m.GoogleMapsLink, &m.Type, &m.Version); err != nil {
 			return nil, err
 		}
 		items = append(items, m)
@@ -36,50 +38,57 @@ func (r *LocationsRepo) List(ctx context.Context) ([]models.Location, error) {
 	return items, rows.Err()
 }
 
-func (r *LocationsRepo) Get(ctx context.Context, id string) (models.Location, error) {
+func (r *LocationsRepo) Get(ctx context.Context, orgID, id string) (models.Location, error) {
 	var m models.Location
-	err := scanOne(ctx, r.Pool.QueryRow(ctx, `
-		SELECT id, name, COALESCE(address,''), COALESCE(google_maps_link,''), COALESCE(type,'')
-		FROM locations WHERE id = $1
-	`, id), &m, func() error {
-		return r.Pool.QueryRow(ctx, `
-			SELECT id, name, COALESCE(address,''), COALESCE(google_maps_link,''), COALESCE(type,'')
-			FROM locations WHERE id = $1
-		`, id).Scan(&m.ID, &m.Name, &m.Address, &m.GoogleMapsLink, &m.Type)
+	row := r.Pool.QueryRow(ctx, `
+		SELECT id, name, COALESCE(address,''), COALESCE(google_maps_link,''), COALESCE(type,''), version
+		FROM locations WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL
+	`, id, orgID)
+	err := scanOne(ctx, row, &m, func() error {
+		return row.Scan(&m.ID, &m.Name, &m.Address, &m.GoogleMapsLink, &m.Type, &m.Version)
 	})
 	return m, err
 }
 
-func (r *LocationsRepo) Create(ctx context.Context, in models.Location) (models.Location, error) {
+func (r *LocationsRepo) Create(ctx context.Context, orgID string, in models.Location) (models.Location, error) {
 	if in.ID == "" {
 		in.ID = uuid.NewString()
 	}
 	_, err := r.Pool.Exec(ctx, `
-		INSERT INTO locations (id, name, address, google_maps_link, type)
-		VALUES ($1,$2,$3,$4,$5)
-	`, in.ID, in.Name, in.Address, in.GoogleMapsLink, in.Type)
+		INSERT INTO locations (id, organization_id, name, address, google_maps_link, type)
+		VALUES ($1,$2,$3,$4,$5,$6)
+	`, in.ID, orgID, in.Name, in.Address, in.GoogleMapsLink, in.Type)
+	in.Version = 1
 	return in, err
 }
 
-func (r *LocationsRepo) Update(ctx context.Context, id string, in models.Location) (models.Location, error) {
-	tag, err := r.Pool.Exec(ctx, `
+// Update replaces the location. If in.Version is set, the update only applies to that
+// version of the row and fails with ErrVersionMismatch otherwise.
+func (r *LocationsRepo) Update(ctx context.Context, orgID, id string, in models.Location) (models.Location, error) {
+	row := r.Pool.QueryRow(ctx, `
 		UPDATE locations
-		SET name=$2, address=$3, google_maps_link=$4, type=$5
-		WHERE id=$1
-	`, id, in.Name, in.Address, in.GoogleMapsLink, in.Type)
-	if err != nil {
+		SET name=$3, address=$4, google_maps_link=$5, type=$6, version=version+1
+		WHERE id=$1 AND organization_id=$2 AND deleted_at IS NULL AND ($7 = 0 OR version = $7)
+		RETURNING version
+	`, id, orgID, in.Name, in.Address, in.GoogleMapsLink, in.Type, in.Version)
+	if err := scanOne(ctx, row, &in, func() error { return row.Scan(&in.Version) }); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			err = missedUpdate(ctx, r.Pool, in.Version, `SELECT EXISTS (SELECT 1 FROM locations WHERE id=$1 AND organization_id=$2 AND deleted_at IS NULL)`, id, orgID)
+		}
 		return models.Location{}, err
 	}
-	if tag.RowsAffected() == 0 {
-		return models.Location{}, ErrNotFound
-	}
 	in.ID = id
 	return in, nil
 }
 
-func (r *LocationsRepo) Delete(ctx context.Context, id string) error {
-	_, err := r.Pool.Exec(ctx, `DELETE FROM locations WHERE id=$1`, id)
-	return err
+// Delete moves the location to the trash (see TrashRepo). If blocks, movements or other
+// records still use it, it fails with *InUseError unless opts say to replace or remove
+// those references. It returns the references found and the blocks and movements changed.
+func (r *LocationsRepo) Delete(ctx context.Context, orgID, id string, opts DeleteOptions) ([]models.Reference, []ChangedRow, error) {
+	return r.deleteReferenced(ctx, locationReferences, orgID, id, opts)
 }
 
-
+// Missing returns the given IDs that are not locations of the organization.
+func (r *LocationsRepo) Missing(ctx context.Context, orgID string, ids []string) ([]string, error) {
+	return missingIDs(ctx, r.Pool, "locations", orgID, ids)
+}
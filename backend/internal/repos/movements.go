@@ -2,6 +2,7 @@ package repos
 
 import (
 	"context"
+	"errors"
 	"strconv"
 
 	"planning-system/backend/internal/models"
@@ -16,16 +17,16 @@ func NewMovementsRepo(pool *pgxpool.Pool) *MovementsRepo {
 	return &MovementsRepo{RepoBase{Pool: pool}}
 }
 
-func (r *MovementsRepo) ListByDay(ctx context.Context, dayID string) ([]models.Movement, error) {
+func (r *MovementsRepo) ListByDay(ctx context.Context, orgID, dayID string) ([]models.Movement, error) {
 	rows, err := r.Pool.Query(ctx, `
 		SELECT id, day_id, title, COALESCE(description,''), 
 		       from_location_id::text, to_location_id::text, 
 		       to_char(from_time,'HH24:MI') AS from_time, to_time_type, 
-		       COALESCE(to_char(to_time,'HH24:MI'),''), driving_minutes
+		       COALESCE(to_char(to_time,'HH24:MI'),''), to_day_offset, driving_minutes, version
 		FROM movements
-		WHERE day_id=$1
-		ORDER BY from_time ASC
-	`, dayID)
+		WHERE day_id=$1 AND deleted_at IS NULL AND day_id IN (SELECT d.id FROM days d JOIN events e ON e.id = d.event_id WHERE e.organization_id = $2 AND d.deleted_at IS NULL)
+		ORDER BY from_time ASC, to_day_offset ASC, to_time ASC NULLS LAST
+	`, dayID, orgID)
 	if err != nil {
 		return nil, err
 	}
@@ -36,7 +37,7 @@ func (r *MovementsRepo) ListByDay(ctx context.Context, dayID string) ([]models.M
 		var fromLoc, toLoc *string
 		var toTime string
 		var driving *int
-		if err := rows.Scan(&m.ID, &m.DayID, &m.Title, &m.Description, &fromLoc, &toLoc, &m.FromTime, &m.ToTimeType, &toTime, &driving); err != nil {
+		if err := rows.Scan(&m.ID, &m.DayID, &m.Title, &m.Description, &fromLoc, &toLoc, &m.FromTime, &m.ToTimeType, &toTime, &m.ToDayOffset, &driving, &m.Version); err != nil {
 			return nil, err
 		}
 		// Convert nullable strings to empty string if nil
@@ -122,7 +123,13 @@ func (r *MovementsRepo) ListByDay(ctx context.Context, dayID string) ([]models.M
 	for i := range items {
 		items[i].VehicleAssignments = assignByMovement[items[i].ID]
 	}
-	return items, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := r.localizeMovements(ctx, items); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 func collectMovementIDs(ms []models.Movement) []string {
@@ -134,7 +141,7 @@ func collectMovementIDs(ms []models.Movement) []string {
 }
 
 // ListByDays fetches all movements for multiple days in a single query
-func (r *MovementsRepo) ListByDays(ctx context.Context, dayIDs []string) ([]models.Movement, error) {
+func (r *MovementsRepo) ListByDays(ctx context.Context, orgID string, dayIDs []string) ([]models.Movement, error) {
 	if len(dayIDs) == 0 {
 		return []models.Movement{}, nil
 	}
@@ -142,11 +149,11 @@ func (r *MovementsRepo) ListByDays(ctx context.Context, dayIDs []string) ([]mode
 		SELECT id, day_id, title, COALESCE(description,''), 
 		       from_location_id::text, to_location_id::text, 
 		       to_char(from_time,'HH24:MI') AS from_time, to_time_type, 
-		       COALESCE(to_char(to_time,'HH24:MI'),''), driving_minutes
+		       COALESCE(to_char(to_time,'HH24:MI'),''), to_day_offset, driving_minutes, version
 		FROM movements
-		WHERE day_id = ANY($1::uuid[])
-		ORDER BY day_id, from_time ASC
-	`, dayIDs)
+		WHERE day_id = ANY($1::uuid[]) AND deleted_at IS NULL AND day_id IN (SELECT d.id FROM days d JOIN events e ON e.id = d.event_id WHERE e.organization_id = $2 AND d.deleted_at IS NULL)
+		ORDER BY day_id, from_time ASC, to_day_offset ASC, to_time ASC NULLS LAST
+	`, dayIDs, orgID)
 	if err != nil {
 		return nil, err
 	}
@@ -157,7 +164,7 @@ func (r *MovementsRepo) ListByDays(ctx context.Context, dayIDs []string) ([]mode
 		var fromLoc, toLoc *string
 		var toTime string
 		var driving *int
-		if err := rows.Scan(&m.ID, &m.DayID, &m.Title, &m.Description, &fromLoc, &toLoc, &m.FromTime, &m.ToTimeType, &toTime, &driving); err != nil {
+		if err := rows.Scan(&m.ID, &m.DayID, &m.Title, &m.Description, &fromLoc, &toLoc, &m.FromTime, &m.ToTimeType, &toTime, &m.ToDayOffset, &driving, &m.Version); err != nil {
 			return nil, err
 		}
 		// Convert nullable strings to empty string if nil
@@ -261,12 +268,14 @@ func (r *MovementsRepo) ListByDays(ctx context.Context, dayIDs []string) ([]mode
 			}
 		}
 	}
-	
+	if err := r.localizeMovements(ctx, items); err != nil {
+		return nil, err
+	}
 	return items, nil
 }
 
-func (r *MovementsRepo) Get(ctx context.Context, dayID, id string) (models.Movement, error) {
-	list, err := r.ListByDay(ctx, dayID)
+func (r *MovementsRepo) Get(ctx context.Context, orgID, dayID, id string) (models.Movement, error) {
+	list, err := r.ListByDay(ctx, orgID, dayID)
 	if err != nil {
 		return models.Movement{}, err
 	}
@@ -278,7 +287,7 @@ func (r *MovementsRepo) Get(ctx context.Context, dayID, id string) (models.Movem
 	return models.Movement{}, ErrNotFound
 }
 
-func (r *MovementsRepo) Create(ctx context.Context, in models.Movement) (models.Movement, error) {
+func (r *MovementsRepo) Create(ctx context.Context, orgID string, in models.Movement) (models.Movement, error) {
 	if in.ID == "" {
 		in.ID = uuid.NewString()
 	}
@@ -287,6 +296,9 @@ func (r *MovementsRepo) Create(ctx context.Context, in models.Movement) (models.
 		return models.Movement{}, err
 	}
 	defer rollbackTx(tx)
+	if err := dayInOrganization(ctx, tx, orgID, in.DayID); err != nil {
+		return models.Movement{}, err
+	}
 	// Convert ToTime and driving time to DB format
 	var toTime interface{}
 	var drivingMinutes *int
@@ -319,9 +331,9 @@ func (r *MovementsRepo) Create(ctx context.Context, in models.Movement) (models.
 		toLoc = in.ToLocationID
 	}
 	_, err = tx.Exec(ctx, `
-		INSERT INTO movements (id, day_id, title, description, from_location_id, to_location_id, from_time, to_time_type, to_time, driving_minutes)
-		VALUES ($1,$2,$3,$4,NULLIF($5,'')::uuid,NULLIF($6,'')::uuid,$7::time,$8, NULLIF($9,'')::time, $10)
-	`, in.ID, in.DayID, in.Title, in.Description, fromLoc, toLoc, in.FromTime, in.ToTimeType, toTime, drivingMinutes)
+		INSERT INTO movements (id, day_id, title, description, from_location_id, to_location_id, from_time, to_time_type, to_time, driving_minutes, to_day_offset)
+		VALUES ($1,$2,$3,$4,NULLIF($5,'')::uuid,NULLIF($6,'')::uuid,$7::time,$8, NULLIF($9,'')::time, $10, $11)
+	`, in.ID, in.DayID, in.Title, in.Description, fromLoc, toLoc, in.FromTime, in.ToTimeType, toTime, drivingMinutes, in.ToDayOffset)
 	if err != nil {
 		return models.Movement{}, err
 	}
@@ -344,13 +356,38 @@ func (r *MovementsRepo) Create(ctx context.Context, in models.Movement) (models.
 			}
 		}
 	}
+	if err := movementRevisions.record(ctx, tx, in.ID, true); err != nil {
+		return models.Movement{}, err
+	}
 	if err := tx.Commit(ctx); err != nil {
 		return models.Movement{}, err
 	}
+	in.Version = 1
+	if err := r.localizeMovement(ctx, &in); err != nil {
+		return models.Movement{}, err
+	}
 	return in, nil
 }
 
-func (r *MovementsRepo) Update(ctx context.Context, id string, in models.Movement) (models.Movement, error) {
+// MovementRelations selects the lists of a movement that an update replaces.
+type MovementRelations struct {
+	VehicleAssignments bool
+}
+
+// AllMovementRelations replaces every list of the movement.
+var AllMovementRelations = MovementRelations{VehicleAssignments: true}
+
+// Update replaces the movement with its vehicle assignments. If in.Version is set, the
+// update only applies to that version of the row and fails with ErrVersionMismatch otherwise.
+// The movement as it was and as it becomes are kept as revisions.
+func (r *MovementsRepo) Update(ctx context.Context, orgID, id string, in models.Movement) (models.Movement, error) {
+	return r.Patch(ctx, orgID, id, in, AllMovementRelations)
+}
+
+// Patch is Update for a partial change: the movement's own fields are all written, but
+// its vehicle assignments are only replaced if rel says so. The returned movement
+// carries the assignments of in.
+func (r *MovementsRepo) Patch(ctx context.Context, orgID, id string, in models.Movement, rel MovementRelations) (models.Movement, error) {
 	tx, err := r.Pool.Begin(ctx)
 	if err != nil {
 		return models.Movement{}, err
@@ -387,50 +424,67 @@ func (r *MovementsRepo) Update(ctx context.Context, id string, in models.Movemen
 	if in.ToLocationID != "" {
 		toLoc = in.ToLocationID
 	}
-	tag, err := tx.Exec(ctx, `
+	if err := movementRevisions.record(ctx, tx, id, false); err != nil {
+		return models.Movement{}, err
+	}
+	row := tx.QueryRow(ctx, `
 		UPDATE movements
 		SET title=$2, description=$3, from_location_id=NULLIF($4,'')::uuid, to_location_id=NULLIF($5,'')::uuid,
-		    from_time=$6::time, to_time_type=$7, to_time=NULLIF($8,'')::time, driving_minutes=$9
-		WHERE id=$1
-	`, id, in.Title, in.Description, fromLoc, toLoc, in.FromTime, in.ToTimeType, toTime, drivingMinutes)
-	if err != nil {
+		    from_time=$6::time, to_time_type=$7, to_time=NULLIF($8,'')::time, driving_minutes=$9, to_day_offset=$11, version=version+1
+		WHERE id=$1 AND day_id IN (SELECT d.id FROM days d JOIN events e ON e.id = d.event_id WHERE e.organization_id = $10 AND d.deleted_at IS NULL)
+		  AND deleted_at IS NULL AND ($12 = 0 OR version = $12)
+		RETURNING day_id::text, version
+	`, id, in.Title, in.Description, fromLoc, toLoc, in.FromTime, in.ToTimeType, toTime, drivingMinutes, orgID, in.ToDayOffset, in.Version)
+	if err := scanOne(ctx, row, &in.DayID, func() error { return row.Scan(&in.DayID, &in.Version) }); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			err = missedUpdate(ctx, tx, in.Version, `SELECT EXISTS (SELECT 1 FROM movements WHERE id=$1 AND deleted_at IS NULL AND day_id IN (SELECT d.id FROM days d JOIN events e ON e.id = d.event_id WHERE e.organization_id = $2 AND d.deleted_at IS NULL))`, id, orgID)
+		}
 		return models.Movement{}, err
 	}
-	if tag.RowsAffected() == 0 {
-		return models.Movement{}, ErrNotFound
-	}
 	// replace assignments
-	if _, err := tx.Exec(ctx, `DELETE FROM vehicle_assignment_passengers WHERE assignment_id IN (SELECT id FROM vehicle_assignments WHERE movement_id=$1)`, id); err != nil {
-		return models.Movement{}, err
-	}
-	if _, err := tx.Exec(ctx, `DELETE FROM vehicle_assignments WHERE movement_id=$1`, id); err != nil {
-		return models.Movement{}, err
-	}
-	for _, a := range in.VehicleAssignments {
-		aid := uuid.NewString()
-		if _, err := tx.Exec(ctx, `
-			INSERT INTO vehicle_assignments (id, movement_id, vehicle_id, driver_id)
-			VALUES ($1,$2,$3,NULLIF($4,'')::uuid)
-		`, aid, id, a.VehicleID, a.DriverID); err != nil {
+	if rel.VehicleAssignments {
+		if _, err := tx.Exec(ctx, `DELETE FROM vehicle_assignment_passengers WHERE assignment_id IN (SELECT id FROM vehicle_assignments WHERE movement_id=$1)`, id); err != nil {
 			return models.Movement{}, err
 		}
-		for _, pid := range a.ParticipantIDs {
+		if _, err := tx.Exec(ctx, `DELETE FROM vehicle_assignments WHERE movement_id=$1`, id); err != nil {
+			return models.Movement{}, err
+		}
+		for _, a := range in.VehicleAssignments {
+			aid := uuid.NewString()
 			if _, err := tx.Exec(ctx, `
-				INSERT INTO vehicle_assignment_passengers (assignment_id, participant_id) VALUES ($1,$2)
-			`, aid, pid); err != nil {
+				INSERT INTO vehicle_assignments (id, movement_id, vehicle_id, driver_id)
+				VALUES ($1,$2,$3,NULLIF($4,'')::uuid)
+			`, aid, id, a.VehicleID, a.DriverID); err != nil {
 				return models.Movement{}, err
 			}
+			for _, pid := range a.ParticipantIDs {
+				if _, err := tx.Exec(ctx, `
+					INSERT INTO vehicle_assignment_passengers (assignment_id, participant_id) VALUES ($1,$2)
+				`, aid, pid); err != nil {
+					return models.Movement{}, err
+				}
+			}
 		}
 	}
+	if err := movementRevisions.record(ctx, tx, id, true); err != nil {
+		return models.Movement{}, err
+	}
 	if err := tx.Commit(ctx); err != nil {
 		return models.Movement{}, err
 	}
 	in.ID = id
+	if err := r.localizeMovement(ctx, &in); err != nil {
+		return models.Movement{}, err
+	}
 	return in, nil
 }
 
-func (r *MovementsRepo) Delete(ctx context.Context, id string) error {
-	_, err := r.Pool.Exec(ctx, `DELETE FROM movements WHERE id=$1`, id)
+// Delete moves the movement to the trash; its vehicle assignments stay with it.
+func (r *MovementsRepo) Delete(ctx context.Context, orgID, id string) error {
+	_, err := r.Pool.Exec(ctx, `
+		UPDATE movements SET deleted_at=now()
+		WHERE id=$1 AND deleted_at IS NULL AND day_id IN (SELECT d.id FROM days d JOIN events e ON e.id = d.event_id WHERE e.organization_id = $2 AND d.deleted_at IS NULL)
+	`, id, orgID)
 	return err
 }
 
@@ -0,0 +1,50 @@
+package repos
+
+import (
+	"context"
+
+	"planning-system/backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type OrganizationsRepo struct{ RepoBase }
+
+func NewOrganizationsRepo(pool *pgxpool.Pool) *OrganizationsRepo {
+	return &OrganizationsRepo{RepoBase{Pool: pool}}
+}
+
+func (r *OrganizationsRepo) List(ctx context.Context) ([]models.Organization, error) {
+	rows, err := r.Pool.Query(ctx, `SELECT id, name FROM organizations ORDER BY name ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]models.Organization, 0)
+	for rows.Next() {
+		var m models.Organization
+		if err := rows.Scan(&m.ID, &m.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, m)
+	}
+	return items, rows.Err()
+}
+
+func (r *OrganizationsRepo) Get(ctx context.Context, id string) (models.Organization, error) {
+	var m models.Organization
+	row := r.Pool.QueryRow(ctx, `SELECT id, name FROM organizations WHERE id = $1`, id)
+	err := scanOne(ctx, row, &m, func() error {
+		return row.Scan(&m.ID, &m.Name)
+	})
+	return m, err
+}
+
+func (r *OrganizationsRepo) Create(ctx context.Context, in models.Organization) (models.Organization, error) {
+	if in.ID == "" {
+		in.ID = uuid.NewString()
+	}
+	_, err := r.Pool.Exec(ctx, `INSERT INTO organizations (id, name) VALUES ($1,$2)`, in.ID, in.Name)
+	return in, err
+}
@@ -0,0 +1,79 @@
+package repos
+
+import (
+	"context"
+
+	"planning-system/backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ParticipantTokensRepo struct{ RepoBase }
+
+func NewParticipantTokensRepo(pool *pgxpool.Pool) *ParticipantTokensRepo {
+	return &ParticipantTokensRepo{RepoBase{Pool: pool}}
+}
+
+func (r *ParticipantTokensRepo) List(ctx context.Context, orgID, participantID string) ([]models.ParticipantAccessToken, error) {
+	rows, err := r.Pool.Query(ctx, `
+		SELECT id, organization_id, participant_id, label, expires_at, created_at, revoked_at
+		FROM participant_access_tokens
+		WHERE organization_id = $1 AND participant_id = $2
+		ORDER BY created_at DESC
+	`, orgID, participantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]models.ParticipantAccessToken, 0)
+	for rows.Next() {
+		var t models.ParticipantAccessToken
+		if err := rows.Scan(&t.ID, &t.OrganizationID, &t.ParticipantID, &t.Label, &t.ExpiresAt, &t.CreatedAt, &t.RevokedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, t)
+	}
+	return items, rows.Err()
+}
+
+// GetActive returns the token row if it is neither revoked nor expired.
+func (r *ParticipantTokensRepo) GetActive(ctx context.Context, id string) (models.ParticipantAccessToken, error) {
+	var t models.ParticipantAccessToken
+	row := r.Pool.QueryRow(ctx, `
+		SELECT id, organization_id, participant_id, label, expires_at, created_at, revoked_at
+		FROM participant_access_tokens
+		WHERE id = $1 AND revoked_at IS NULL AND expires_at > now()
+	`, id)
+	err := scanOne(ctx, row, &t, func() error {
+		return row.Scan(&t.ID, &t.OrganizationID, &t.ParticipantID, &t.Label, &t.ExpiresAt, &t.CreatedAt, &t.RevokedAt)
+	})
+	return t, err
+}
+
+func (r *ParticipantTokensRepo) Create(ctx context.Context, in models.ParticipantAccessToken, createdBy string) (models.ParticipantAccessToken, error) {
+	if in.ID == "" {
+		in.ID = uuid.NewString()
+	}
+	err := r.Pool.QueryRow(ctx, `
+		INSERT INTO participant_access_tokens (id, organization_id, participant_id, label, expires_at, created_by)
+		VALUES ($1,$2,$3,$4,$5,NULLIF($6,'')::uuid)
+		RETURNING created_at
+	`, in.ID, in.OrganizationID, in.ParticipantID, in.Label, in.ExpiresAt, createdBy).Scan(&in.CreatedAt)
+	return in, err
+}
+
+// Revoke marks the participant's token as revoked; revoking twice is a no-op.
+func (r *ParticipantTokensRepo) Revoke(ctx context.Context, orgID, participantID, id string) error {
+	tag, err := r.Pool.Exec(ctx, `
+		UPDATE participant_access_tokens SET revoked_at = COALESCE(revoked_at, now())
+		WHERE id = $1 AND organization_id = $2 AND participant_id = $3
+	`, id, orgID, participantID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package repos
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"strconv"
 
@@ -17,9 +18,9 @@ func NewParticipantsRepo(pool *pgxpool.Pool) *ParticipantsRepo {
 	return &ParticipantsRepo{RepoBase{Pool: pool}}
 }
 
-func (r *ParticipantsRepo) List(ctx context.Context, p PageParams, search, role string) ([]models.Participant, int64, error) {
-	args := []any{}
-	where := []string{}
+func (r *ParticipantsRepo) List(ctx context.Context, orgID string, p PageParams, search, role string) ([]models.Participant, int64, error) {
+	args := []any{orgID}
+	where := []string{"organization_id = $1", "deleted_at IS NULL"}
 	if search != "" {
 		args = append(args, "%"+strings.ToLower(search)+"%")
 		where = append(where, "(LOWER(name) LIKE $"+itoa(len(args))+" OR LOWER(email) LIKE $"+itoa(len(args))+" OR LOWER(phone) LIKE $"+itoa(len(args))+")")
@@ -29,12 +30,10 @@ func (r *ParticipantsRepo) List(ctx context.Context, p PageParams, search, role
 		where = append(where, "EXISTS (SELECT 1 FROM unnest(roles) r WHERE LOWER(r) = $"+itoa(len(args))+")")
 	}
 	q := `
-		SELECT id, name, roles, COALESCE(email,''), COALESCE(phone,''), languages
+		SELECT id, name, roles, COALESCE(email,''), COALESCE(phone,''), languages, version
 		FROM participants
 	`
-	if len(where) > 0 {
-		q += " WHERE " + strings.Join(where, " AND ")
-	}
+	q += " WHERE " + strings.Join(where, " AND ")
 	q += " ORDER BY name ASC LIMIT $" + itoa(len(args)+1) + " OFFSET $" + itoa(len(args)+2)
 	args = append(args, p.Limit, p.Offset)
 	rows, err := r.Pool.Query(ctx, q, args...)
@@ -45,70 +44,74 @@ func (r *ParticipantsRepo) List(ctx context.Context, p PageParams, search, role
 	items := make([]models.Participant, 0) // Initialize as empty slice, not nil
 	for rows.Next() {
 		var m models.Participant
-		if err := rows.Scan(&m.ID, &m.Name, &m.Roles, &m.Email, &m.Phone, &m.Languages); err != nil {
+		if err := rows.Scan(&m.ID, &m.Name, &m.Roles, &m.Email, &m.Phone, &m.Languages, &m.Version); err != nil {
 			return nil, 0, err
 		}
 		items = append(items, m)
 	}
 	var total int64
-	countQ := "SELECT COUNT(*) FROM participants"
-	if len(where) > 0 {
-		countQ += " WHERE " + strings.Join(where, " AND ")
-	}
+	countQ := "SELECT COUNT(*) FROM participants WHERE " + strings.Join(where, " AND ")
 	if err := r.Pool.QueryRow(ctx, countQ, args[:len(args)-2]...).Scan(&total); err != nil {
 		return nil, 0, err
 	}
 	return items, total, rows.Err()
 }
 
-func (r *ParticipantsRepo) Get(ctx context.Context, id string) (models.Participant, error) {
+func (r *ParticipantsRepo) Get(ctx context.Context, orgID, id string) (models.Participant, error) {
 	var m models.Participant
-	err := scanOne(ctx, r.Pool.QueryRow(ctx, `
-		SELECT id, name, roles, COALESCE(email,''), COALESCE(phone,''), languages
-		FROM participants WHERE id = $1
-	`, id), &m, func() error {
-		return r.Pool.QueryRow(ctx, `
-			SELECT id, name, roles, COALESCE(email,''), COALESCE(phone,''), languages
-			FROM participants WHERE id = $1
-		`, id).Scan(&m.ID, &m.Name, &m.Roles, &m.Email, &m.Phone, &m.Languages)
+	row := r.Pool.QueryRow(ctx, `
+		SELECT id, name, roles, COALESCE(email,''), COALESCE(phone,''), languages, version
+		FROM participants WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL
+	`, id, orgID)
+	err := scanOne(ctx, row, &m, func() error {
+		return row.Scan(&m.ID, &m.Name, &m.Roles, &m.Email, &m.Phone, &m.Languages, &m.Version)
 	})
 	return m, err
 }
 
-func (r *ParticipantsRepo) Create(ctx context.Context, in models.Participant) (models.Participant, error) {
+func (r *ParticipantsRepo) Create(ctx context.Context, orgID string, in models.Participant) (models.Participant, error) {
 	if in.ID == "" {
 		in.ID = uuid.NewString()
 	}
 	_, err := r.Pool.Exec(ctx, `
-		INSERT INTO participants (id, name, roles, email, phone, languages)
-		VALUES ($1,$2,$3,$4,$5,$6)
-	`, in.ID, in.Name, in.Roles, in.Email, in.Phone, in.Languages)
+		INSERT INTO participants (id, organization_id, name, roles, email, phone, languages)
+		VALUES ($1,$2,$3,$4,$5,$6,$7)
+	`, in.ID, orgID, in.Name, in.Roles, in.Email, in.Phone, in.Languages)
+	in.Version = 1
 	return in, err
 }
 
-func (r *ParticipantsRepo) Update(ctx context.Context, id string, in models.Participant) (models.Participant, error) {
-	tag, err := r.Pool.Exec(ctx, `
+// Update replaces the participant. If in.Version is set, the update only applies to that
+// version of the row and fails with ErrVersionMismatch otherwise.
+func (r *ParticipantsRepo) Update(ctx context.Context, orgID, id string, in models.Participant) (models.Participant, error) {
+	row := r.Pool.QueryRow(ctx, `
 		UPDATE participants
-		SET name=$2, roles=$3, email=$4, phone=$5, languages=$6
-		WHERE id=$1
-	`, id, in.Name, in.Roles, in.Email, in.Phone, in.Languages)
-	if err != nil {
+		SET name=$3, roles=$4, email=$5, phone=$6, languages=$7, version=version+1
+		WHERE id=$1 AND organization_id=$2 AND deleted_at IS NULL AND ($8 = 0 OR version = $8)
+		RETURNING version
+	`, id, orgID, in.Name, in.Roles, in.Email, in.Phone, in.Languages, in.Version)
+	if err := scanOne(ctx, row, &in, func() error { return row.Scan(&in.Version) }); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			err = missedUpdate(ctx, r.Pool, in.Version, `SELECT EXISTS (SELECT 1 FROM participants WHERE id=$1 AND organization_id=$2 AND deleted_at IS NULL)`, id, orgID)
+		}
 		return models.Participant{}, err
 	}
-	if tag.RowsAffected() == 0 {
-		return models.Participant{}, ErrNotFound
-	}
 	in.ID = id
 	return in, nil
 }
 
-func (r *ParticipantsRepo) Delete(ctx context.Context, id string) error {
-	_, err := r.Pool.Exec(ctx, `DELETE FROM participants WHERE id=$1`, id)
-	return err
+// Delete moves the participant to the trash (see TrashRepo). If blocks, movements or other
+// records still use it, it fails with *InUseError unless opts say to replace or remove
+// those references. It returns the references found and the blocks and movements changed.
+func (r *ParticipantsRepo) Delete(ctx context.Context, orgID, id string, opts DeleteOptions) ([]models.Reference, []ChangedRow, error) {
+	return r.deleteReferenced(ctx, participantReferences, orgID, id, opts)
+}
+
+// Missing returns the given IDs that are not participants of the organization.
+func (r *ParticipantsRepo) Missing(ctx context.Context, orgID string, ids []string) ([]string, error) {
+	return missingIDs(ctx, r.Pool, "participants", orgID, ids)
 }
 
 func itoa(i int) string {
 	return strconv.Itoa(i)
 }
-
-
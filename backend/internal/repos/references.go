@@ -0,0 +1,349 @@
+package repos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"planning-system/backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// DeleteOptions say what happens to the references of a location, vehicle or
+// participant that is being deleted. Without either option a referenced row is
+// not deleted.
+type DeleteOptions struct {
+	// Force removes the references: fields are cleared, list entries and vehicle
+	// assignments are dropped.
+	Force bool
+	// ReplaceWith points the references to this row of the same kind instead.
+	ReplaceWith string
+}
+
+// InUseError is returned when deleting a row that is still referenced.
+type InUseError struct {
+	References []models.Reference
+}
+
+func (e *InUseError) Error() string {
+	return fmt.Sprintf("still referenced by %d record(s)", len(e.References))
+}
+
+// ChangedRow is a block or movement that a delete changed by replacing or removing its
+// references, as snapshots (the JSON of its revisions) before and after the change.
+type ChangedRow struct {
+	Type          string // "block" | "movement"
+	ID            string
+	Before, After json.RawMessage
+}
+
+// ErrReplacementNotFound is returned when DeleteOptions.ReplaceWith is not a row of the organization.
+var ErrReplacementNotFound = errors.New("replacement not found")
+
+// Only live rows count as references: rows in the trash keep pointing at what they
+// pointed at when they were deleted. The rows of archived events are references too,
+// but they are read-only: a forced or replacing delete only rewrites the writable ones
+// and leaves those pointing at the deleted row. $2 is the organization.
+const (
+	liveDays            = `(SELECT d.id FROM days d JOIN events e ON e.id = d.event_id WHERE e.organization_id = $2 AND d.deleted_at IS NULL)`
+	liveBlocks          = `(SELECT id FROM blocks WHERE deleted_at IS NULL AND day_id IN ` + liveDays + `)`
+	liveMovements       = `(SELECT id FROM movements WHERE deleted_at IS NULL AND day_id IN ` + liveDays + `)`
+	writableDays        = `(SELECT d.id FROM days d JOIN events e ON e.id = d.event_id WHERE e.organization_id = $2 AND e.archived_at IS NULL AND d.deleted_at IS NULL)`
+	writableBlocks      = `(SELECT id FROM blocks WHERE deleted_at IS NULL AND day_id IN ` + writableDays + `)`
+	writableMovements   = `(SELECT id FROM movements WHERE deleted_at IS NULL AND day_id IN ` + writableDays + `)`
+	writableAssignments = `(SELECT id FROM vehicle_assignments WHERE movement_id IN ` + writableMovements + `)`
+)
+
+// referenced describes how to find, and replace or remove, the references to a row of
+// table. Every statement takes the row as $1 and the organization as $2; replace
+// statements take the replacement as $3. bump increments the version of the blocks
+// and movements whose relations change.
+type referenced struct {
+	table      string
+	references string
+	bump       []string
+	replace    []string
+	remove     []string
+}
+
+// blockListTables are the participant lists of a block and the field exposing each.
+var blockListTables = []struct{ table, field string }{
+	{"block_participants", "participantsIds"},
+	{"block_advance_participants", "advanceParticipantIds"},
+	{"block_met_by_participants", "metByParticipantIds"},
+}
+
+var locationReferences = referenced{
+	table: "locations",
+	references: `
+		SELECT 'block' AS type, b.id::text AS id, b.title AS label, 'locationId' AS field,
+		       d.event_id::text AS event_id, d.id::text AS day_id, to_char(d.date,'YYYY-MM-DD') AS date, '' AS movement_id
+		FROM blocks b JOIN days d ON d.id = b.day_id
+		WHERE b.location_id = $1 AND b.id IN ` + liveBlocks + `
+		UNION ALL
+		SELECT 'movement', m.id::text, m.title, 'fromLocationId', d.event_id::text, d.id::text, to_char(d.date,'YYYY-MM-DD'), ''
+		FROM movements m JOIN days d ON d.id = m.day_id
+		WHERE m.from_location_id = $1 AND m.id IN ` + liveMovements + `
+		UNION ALL
+		SELECT 'movement', m.id::text, m.title, 'toLocationId', d.event_id::text, d.id::text, to_char(d.date,'YYYY-MM-DD'), ''
+		FROM movements m JOIN days d ON d.id = m.day_id
+		WHERE m.to_location_id = $1 AND m.id IN ` + liveMovements + `
+		UNION ALL
+		SELECT 'vehicle', v.id::text, v.label, 'originationLocationId', '', '', '', ''
+		FROM vehicles v
+		WHERE v.origination_location_id = $1 AND v.organization_id = $2 AND v.deleted_at IS NULL
+		UNION ALL
+		SELECT 'block_template', t.id::text, t.name, 'locationId', '', '', '', ''
+		FROM block_templates t
+		WHERE t.location_id = $1 AND t.organization_id = $2
+		ORDER BY date, type, label
+	`,
+	replace: setLocation("$3::uuid"),
+	remove:  setLocation("NULL"),
+}
+
+// setLocation points every reference to the location in $1 to value.
+func setLocation(value string) []string {
+	return []string{
+		`UPDATE blocks SET location_id = ` + value + `, version = version+1 WHERE location_id = $1 AND id IN ` + writableBlocks,
+		`UPDATE movements SET from_location_id = ` + value + `, version = version+1 WHERE from_location_id = $1 AND id IN ` + writableMovements,
+		`UPDATE movements SET to_location_id = ` + value + `, version = version+1 WHERE to_location_id = $1 AND id IN ` + writableMovements,
+		`UPDATE vehicles SET origination_location_id = ` + value + `, version = version+1 WHERE origination_location_id = $1 AND organization_id = $2 AND deleted_at IS NULL`,
+		`UPDATE block_templates SET location_id = ` + value + ` WHERE location_id = $1 AND organization_id = $2`,
+	}
+}
+
+var vehicleReferences = referenced{
+	table: "vehicles",
+	references: `
+		SELECT 'vehicle_assignment' AS type, va.id::text AS id, m.title AS label, 'vehicleId' AS field,
+		       d.event_id::text AS event_id, d.id::text AS day_id, to_char(d.date,'YYYY-MM-DD') AS date, m.id::text AS movement_id
+		FROM vehicle_assignments va JOIN movements m ON m.id = va.movement_id JOIN days d ON d.id = m.day_id
+		WHERE va.vehicle_id = $1 AND m.id IN ` + liveMovements + `
+		ORDER BY date, label
+	`,
+	bump: []string{
+		`UPDATE movements SET version = version+1 WHERE id IN (SELECT movement_id FROM vehicle_assignments WHERE vehicle_id = $1) AND id IN ` + writableMovements,
+	},
+	replace: []string{
+		`UPDATE vehicle_assignments SET vehicle_id = $3::uuid WHERE vehicle_id = $1 AND movement_id IN ` + writableMovements,
+	},
+	remove: []string{
+		`DELETE FROM vehicle_assignments WHERE vehicle_id = $1 AND movement_id IN ` + writableMovements,
+	},
+}
+
+var participantReferences = func() referenced {
+	ref := referenced{table: "participants"}
+	var blockLists string
+	for _, l := range blockListTables {
+		blockLists += `
+		SELECT 'block', b.id::text, b.title, '` + l.field + `', d.event_id::text, d.id::text, to_char(d.date,'YYYY-MM-DD'), ''
+		FROM ` + l.table + ` bp JOIN blocks b ON b.id = bp.block_id JOIN days d ON d.id = b.day_id
+		WHERE bp.participant_id = $1 AND b.id IN ` + liveBlocks + `
+		UNION ALL`
+		// Moving an entry to a participant already on the list merges the two.
+		ref.replace = append(ref.replace, `
+			WITH moved AS (DELETE FROM `+l.table+` WHERE participant_id = $1 AND block_id IN `+writableBlocks+` RETURNING block_id)
+			INSERT INTO `+l.table+` (block_id, participant_id) SELECT block_id, $3::uuid FROM moved ON CONFLICT DO NOTHING
+		`)
+		ref.remove = append(ref.remove, `DELETE FROM `+l.table+` WHERE participant_id = $1 AND block_id IN `+writableBlocks)
+	}
+	ref.references = `
+		SELECT 'vehicle_assignment' AS type, va.id::text AS id, m.title AS label, 'driverId' AS field,
+		       d.event_id::text AS event_id, d.id::text AS day_id, to_char(d.date,'YYYY-MM-DD') AS date, m.id::text AS movement_id
+		FROM vehicle_assignments va JOIN movements m ON m.id = va.movement_id JOIN days d ON d.id = m.day_id
+		WHERE va.driver_id = $1 AND m.id IN ` + liveMovements + `
+		UNION ALL
+		SELECT 'vehicle_assignment', va.id::text, m.title, 'participantIds', d.event_id::text, d.id::text, to_char(d.date,'YYYY-MM-DD'), m.id::text
+		FROM vehicle_assignment_passengers vp JOIN vehicle_assignments va ON va.id = vp.assignment_id
+		JOIN movements m ON m.id = va.movement_id JOIN days d ON d.id = m.day_id
+		WHERE vp.participant_id = $1 AND m.id IN ` + liveMovements + `
+		UNION ALL` + blockLists + `
+		SELECT 'user', u.id::text, u.email, 'participantId', '', '', '', ''
+		FROM users u
+		WHERE u.participant_id = $1 AND u.organization_id = $2
+		ORDER BY date, type, label
+	`
+	ref.bump = []string{
+		`UPDATE blocks SET version = version+1 WHERE id IN (
+			SELECT block_id FROM block_participants WHERE participant_id = $1
+			UNION SELECT block_id FROM block_advance_participants WHERE participant_id = $1
+			UNION SELECT block_id FROM block_met_by_participants WHERE participant_id = $1
+		) AND id IN ` + writableBlocks,
+		`UPDATE movements SET version = version+1 WHERE id IN (
+			SELECT movement_id FROM vehicle_assignments
+			WHERE driver_id = $1 OR id IN (SELECT assignment_id FROM vehicle_assignment_passengers WHERE participant_id = $1)
+		) AND id IN ` + writableMovements,
+	}
+	ref.replace = append(ref.replace,
+		`UPDATE vehicle_assignments SET driver_id = $3::uuid WHERE driver_id = $1 AND movement_id IN `+writableMovements,
+		`
+			WITH moved AS (DELETE FROM vehicle_assignment_passengers WHERE participant_id = $1 AND assignment_id IN `+writableAssignments+` RETURNING assignment_id)
+			INSERT INTO vehicle_assignment_passengers (assignment_id, participant_id) SELECT assignment_id, $3::uuid FROM moved ON CONFLICT DO NOTHING
+		`,
+		`UPDATE users SET participant_id = $3::uuid WHERE participant_id = $1 AND organization_id = $2`,
+	)
+	ref.remove = append(ref.remove,
+		`UPDATE vehicle_assignments SET driver_id = NULL WHERE driver_id = $1 AND movement_id IN `+writableMovements,
+		`DELETE FROM vehicle_assignment_passengers WHERE participant_id = $1 AND assignment_id IN `+writableAssignments,
+		`UPDATE users SET participant_id = NULL WHERE participant_id = $1 AND organization_id = $2`,
+	)
+	return ref
+}()
+
+// deleteReferenced moves the row to the trash unless it is still referenced: then it
+// fails with *InUseError, or replaces or removes the references first as opts say,
+// in the same transaction. It returns the references found and the blocks and movements
+// it changed.
+func (b RepoBase) deleteReferenced(ctx context.Context, ref referenced, orgID, id string, opts DeleteOptions) ([]models.Reference, []ChangedRow, error) {
+	tx, err := b.Pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rollbackTx(tx)
+
+	// Locking the row holds off new references until the row is deleted.
+	var locked string
+	row := tx.QueryRow(ctx, `SELECT id::text FROM `+ref.table+` WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL FOR UPDATE`, id, orgID)
+	if err := scanOne(ctx, row, &locked, func() error { return row.Scan(&locked) }); err != nil {
+		return nil, nil, err
+	}
+	if opts.ReplaceWith != "" {
+		row := tx.QueryRow(ctx, `SELECT id::text FROM `+ref.table+` WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL FOR KEY SHARE`, opts.ReplaceWith, orgID)
+		if err := scanOne(ctx, row, &locked, func() error { return row.Scan(&locked) }); err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return nil, nil, ErrReplacementNotFound
+			}
+			return nil, nil, err
+		}
+	}
+
+	rows, err := tx.Query(ctx, ref.references, id, orgID)
+	if err != nil {
+		return nil, nil, err
+	}
+	refs, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Reference, error) {
+		var r models.Reference
+		err := row.Scan(&r.Type, &r.ID, &r.Label, &r.Field, &r.EventID, &r.DayID, &r.Date, &r.MovementID)
+		return r, err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := markReadOnly(ctx, tx, refs); err != nil {
+		return nil, nil, err
+	}
+
+	var changed []ChangedRow
+	if len(refs) > 0 {
+		stmts, args := ref.remove, []any{id, orgID}
+		switch {
+		case opts.ReplaceWith != "":
+			stmts, args = ref.replace, []any{id, orgID, opts.ReplaceWith}
+		case !opts.Force:
+			return nil, nil, &InUseError{References: refs}
+		}
+		revised := revisedRows(refs)
+		changed = make([]ChangedRow, len(revised))
+		for i, rr := range revised {
+			if err := rr.record(ctx, tx, rr.id, false); err != nil {
+				return nil, nil, err
+			}
+			changed[i] = ChangedRow{Type: rr.typ, ID: rr.id}
+			if changed[i].Before, err = rr.current(ctx, tx, rr.id); err != nil {
+				return nil, nil, err
+			}
+		}
+		for _, q := range ref.bump {
+			if _, err := tx.Exec(ctx, q, id, orgID); err != nil {
+				return nil, nil, err
+			}
+		}
+		for _, q := range stmts {
+			if _, err := tx.Exec(ctx, q, args...); err != nil {
+				return nil, nil, err
+			}
+		}
+		for i, rr := range revised {
+			if err := rr.record(ctx, tx, rr.id, true); err != nil {
+				return nil, nil, err
+			}
+			if changed[i].After, err = rr.current(ctx, tx, rr.id); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE `+ref.table+` SET deleted_at = now() WHERE id = $1 AND organization_id = $2`, id, orgID); err != nil {
+		return nil, nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, err
+	}
+	return refs, changed, nil
+}
+
+// markReadOnly flags the references in archived events, which a delete leaves as they are.
+func markReadOnly(ctx context.Context, tx pgx.Tx, refs []models.Reference) error {
+	var eventIDs []string
+	for _, r := range refs {
+		if r.EventID != "" {
+			eventIDs = append(eventIDs, r.EventID)
+		}
+	}
+	if len(eventIDs) == 0 {
+		return nil
+	}
+	rows, err := tx.Query(ctx, `SELECT id::text FROM events WHERE id::text = ANY($1::text[]) AND archived_at IS NOT NULL`, eventIDs)
+	if err != nil {
+		return err
+	}
+	archived, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return err
+	}
+	for _, id := range archived {
+		for i := range refs {
+			if refs[i].EventID == id {
+				refs[i].ReadOnly = true
+			}
+		}
+	}
+	return nil
+}
+
+// revisedRow is a block or movement changed by replacing or removing references.
+type revisedRow struct {
+	revisioned
+	typ, id string
+}
+
+// revisedRows returns the blocks and movements among refs that are not read-only, each
+// once, so that their new versions are kept as revisions like those written by an update.
+func revisedRows(refs []models.Reference) []revisedRow {
+	seen := map[string]bool{}
+	var out []revisedRow
+	add := func(typ, id string) {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, revisedRow{revisionTables[typ], typ, id})
+		}
+	}
+	for _, ref := range refs {
+		if ref.ReadOnly {
+			continue
+		}
+		switch ref.Type {
+		case "block":
+			add("block", ref.ID)
+		case "movement":
+			add("movement", ref.ID)
+		case "vehicle_assignment":
+			add("movement", ref.MovementID)
+		}
+	}
+	return out
+}
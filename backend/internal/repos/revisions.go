@@ -0,0 +1,140 @@
+package repos
+
+import (
+	"context"
+	"encoding/json"
+
+	"planning-system/backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RevisionsRepo reads the revisions of blocks and movements. They are written by
+// BlocksRepo and MovementsRepo whenever a row is created or updated.
+type RevisionsRepo struct{ RepoBase }
+
+func NewRevisionsRepo(pool *pgxpool.Pool) *RevisionsRepo {
+	return &RevisionsRepo{RepoBase{Pool: pool}}
+}
+
+// revisioned describes a table whose rows keep revisions: the revisions table, its
+// column referencing the row, and an expression rendering the row (as t) as JSON.
+type revisioned struct {
+	table, revisions, key, snapshot string
+}
+
+var blockRevisions = revisioned{"blocks", "block_revisions", "block_id", `jsonb_build_object(
+	'id', t.id, 'type', t.type, 'title', t.title, 'description', COALESCE(t.description,''),
+	'startTime', to_char(t.start_time,'HH24:MI'), 'endTime', COALESCE(to_char(t.end_time,'HH24:MI'),''),
+	'endDayOffset', t.end_day_offset, 'endTimeFixed', t.end_time_fixed, 'locationId', t.location_id,
+	'participantsIds', COALESCE((SELECT jsonb_agg(bp.participant_id ORDER BY bp.participant_id) FROM block_participants bp WHERE bp.block_id = t.id), '[]'::jsonb),
+	'advanceParticipantIds', COALESCE((SELECT jsonb_agg(bp.participant_id ORDER BY bp.participant_id) FROM block_advance_participants bp WHERE bp.block_id = t.id), '[]'::jsonb),
+	'metByParticipantIds', COALESCE((SELECT jsonb_agg(bp.participant_id ORDER BY bp.participant_id) FROM block_met_by_participants bp WHERE bp.block_id = t.id), '[]'::jsonb),
+	'notes', COALESCE(t.notes,''),
+	'scheduleItems', COALESCE((
+		SELECT jsonb_agg(jsonb_build_object(
+			'id', si.id, 'time', to_char(si.time,'HH24:MI'), 'dayOffset', si.day_offset, 'description', si.description,
+			'staffInstructions', COALESCE(si.staff_instructions,''), 'guestInstructions', COALESCE(si.guest_instructions,''), 'notes', si.notes
+		) ORDER BY si.day_offset, si.time, si.id)
+		FROM schedule_items si WHERE si.block_id = t.id
+	), '[]'::jsonb),
+	'version', t.version
+)`}
+
+var movementRevisions = revisioned{"movements", "movement_revisions", "movement_id", `jsonb_build_object(
+	'id', t.id, 'title', t.title, 'description', COALESCE(t.description,''),
+	'fromLocationId', COALESCE(t.from_location_id::text,''), 'toLocationId', COALESCE(t.to_location_id::text,''),
+	'fromTime', to_char(t.from_time,'HH24:MI'), 'toTimeType', t.to_time_type,
+	'toTime', CASE WHEN t.to_time_type = 'fixed' THEN COALESCE(to_char(t.to_time,'HH24:MI'),'') ELSE COALESCE(t.driving_minutes::text,'') END,
+	'toDayOffset', t.to_day_offset,
+	'vehicleAssignments', COALESCE((
+		SELECT jsonb_agg(jsonb_build_object(
+			'vehicleId', va.vehicle_id, 'driverId', va.driver_id,
+			'participantIds', COALESCE((SELECT jsonb_agg(vp.participant_id ORDER BY vp.participant_id) FROM vehicle_assignment_passengers vp WHERE vp.assignment_id = va.id), '[]'::jsonb)
+		) ORDER BY va.vehicle_id, va.id)
+		FROM vehicle_assignments va WHERE va.movement_id = t.id
+	), '[]'::jsonb),
+	'version', t.version
+)`}
+
+// revisionTables maps the entity types that keep revisions to their tables.
+var revisionTables = map[string]revisioned{
+	"block":    blockRevisions,
+	"movement": movementRevisions,
+}
+
+// record stores the current state of the row as the revision of its current version.
+// Called with current false before a change, it keeps a version that has no revision
+// yet (a row created before revisions were kept, or copied) without a time. A version
+// that is already stored is left alone.
+func (rv revisioned) record(ctx context.Context, tx pgx.Tx, id string, current bool) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO `+rv.revisions+` (`+rv.key+`, version, snapshot, created_at)
+		SELECT t.id, t.version, `+rv.snapshot+`, CASE WHEN $2 THEN now() END
+		FROM `+rv.table+` t WHERE t.id = $1
+		ON CONFLICT (`+rv.key+`, version) DO NOTHING
+	`, id, current)
+	return err
+}
+
+// current renders the row as it is now, in the form of its revisions.
+func (rv revisioned) current(ctx context.Context, tx pgx.Tx, id string) (json.RawMessage, error) {
+	var snapshot json.RawMessage
+	err := tx.QueryRow(ctx, `SELECT `+rv.snapshot+` FROM `+rv.table+` t WHERE t.id = $1`, id).Scan(&snapshot)
+	return snapshot, err
+}
+
+// revisionColumns selects a revision (as rv) with the actor of the audit log entry that
+// produced its version.
+const revisionColumns = `
+	SELECT rv.version, COALESCE(a.actor_type,''), a.actor_id::text, COALESCE(a.actor_name,''), rv.created_at, rv.snapshot
+`
+
+func (rv revisioned) from() string {
+	return `
+		FROM ` + rv.revisions + ` rv
+		JOIN ` + rv.table + ` t ON t.id = rv.` + rv.key + `
+		LEFT JOIN LATERAL (
+			SELECT actor_type, actor_id, actor_name FROM audit_log
+			WHERE organization_id = $2 AND entity_id = rv.` + rv.key + ` AND after->>'version' = rv.version::text
+			ORDER BY created_at DESC LIMIT 1
+		) a ON true
+		WHERE rv.` + rv.key + `::text = $1 AND ` + dayOfOrganization
+}
+
+// List returns the revisions of a block or movement, newest first.
+func (r *RevisionsRepo) List(ctx context.Context, orgID, typ, id string) ([]models.Revision, error) {
+	rv, ok := revisionTables[typ]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	rows, err := r.Pool.Query(ctx, revisionColumns+rv.from()+` ORDER BY rv.version DESC`, id, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := make([]models.Revision, 0)
+	for rows.Next() {
+		var it models.Revision
+		if err := rows.Scan(&it.Version, &it.ActorType, &it.ActorID, &it.ActorName, &it.CreatedAt, &it.Snapshot); err != nil {
+			return nil, err
+		}
+		items = append(items, it)
+	}
+	return items, rows.Err()
+}
+
+// Get returns one revision of a block or movement.
+func (r *RevisionsRepo) Get(ctx context.Context, orgID, typ, id string, version int) (models.Revision, error) {
+	var it models.Revision
+	rv, ok := revisionTables[typ]
+	if !ok {
+		return it, ErrNotFound
+	}
+	row := r.Pool.QueryRow(ctx, revisionColumns+rv.from()+` AND rv.version = $3`, id, orgID, version)
+	err := scanOne(ctx, row, &it, func() error {
+		return row.Scan(&it.Version, &it.ActorType, &it.ActorID, &it.ActorName, &it.CreatedAt, &it.Snapshot)
+	})
+	return it, err
+}
@@ -0,0 +1,148 @@
+package repos
+
+import (
+	"context"
+	"errors"
+
+	"planning-system/backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ScheduleItemsRepo changes the schedule items of a block one at a time. Schedule items
+// are part of their block: every change increments the block's version, and is kept
+// as a revision of it, just as a block update is. Items are read with their block.
+type ScheduleItemsRepo struct{ RepoBase }
+
+func NewScheduleItemsRepo(pool *pgxpool.Pool) *ScheduleItemsRepo {
+	return &ScheduleItemsRepo{RepoBase{Pool: pool}}
+}
+
+// ErrForeignScheduleItem is returned when a block update names a schedule item of another block.
+var ErrForeignScheduleItem = errors.New("schedule item belongs to another block")
+
+// Missing returns the given IDs that are not schedule items of the block. For a block
+// not created yet (blockID empty) that is all of them.
+func (r *ScheduleItemsRepo) Missing(ctx context.Context, blockID string, ids []string) ([]string, error) {
+	if blockID == "" || len(ids) == 0 {
+		return ids, nil
+	}
+	rows, err := r.Pool.Query(ctx, `
+		SELECT x FROM unnest($1::text[]) x
+		WHERE NOT EXISTS (SELECT 1 FROM schedule_items si WHERE si.id::text = lower(x) AND si.block_id::text = $2)
+	`, ids, blockID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// Create adds a schedule item to the block. If blockVersion is set, the item is only
+// added while the block is at that version and ErrVersionMismatch is returned otherwise.
+func (r *ScheduleItemsRepo) Create(ctx context.Context, orgID, blockID string, blockVersion int, in models.ScheduleItem) (models.ScheduleItem, error) {
+	in.ID = uuid.NewString()
+	in.BlockID = blockID
+	err := r.changeBlocks(ctx, orgID, blockID, blockVersion, "", func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO schedule_items (id, block_id, time, description, staff_instructions, guest_instructions, notes, day_offset)
+			VALUES ($1,$2,$3::time,$4,$5,$6,$7,$8)
+		`, in.ID, blockID, in.Time, in.Description, in.StaffInstructions, in.GuestInstructions, in.Notes, in.DayOffset)
+		return err
+	})
+	if err != nil {
+		return models.ScheduleItem{}, err
+	}
+	return in, nil
+}
+
+// Update replaces a schedule item of the block, keeping its ID. blockVersion is as for Create.
+func (r *ScheduleItemsRepo) Update(ctx context.Context, orgID, blockID, id string, blockVersion int, in models.ScheduleItem) (models.ScheduleItem, error) {
+	err := r.changeBlocks(ctx, orgID, blockID, blockVersion, "", func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE schedule_items
+			SET time=$3::time, description=$4, staff_instructions=$5, guest_instructions=$6, notes=$7, day_offset=$8
+			WHERE id::text=$1 AND block_id=$2
+		`, id, blockID, in.Time, in.Description, in.StaffInstructions, in.GuestInstructions, in.Notes, in.DayOffset)
+		if err == nil && tag.RowsAffected() == 0 {
+			err = ErrNotFound
+		}
+		return err
+	})
+	if err != nil {
+		return models.ScheduleItem{}, err
+	}
+	in.ID, in.BlockID = id, blockID
+	return in, nil
+}
+
+// Delete removes a schedule item from the block. blockVersion is as for Create.
+func (r *ScheduleItemsRepo) Delete(ctx context.Context, orgID, blockID, id string, blockVersion int) error {
+	return r.changeBlocks(ctx, orgID, blockID, blockVersion, "", func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `DELETE FROM schedule_items WHERE id::text=$1 AND block_id=$2`, id, blockID)
+		if err == nil && tag.RowsAffected() == 0 {
+			err = ErrNotFound
+		}
+		return err
+	})
+}
+
+// Move moves a schedule item, with its ID, time and day offset, from the block to the
+// block toBlockID of the same organization. blockVersion applies to the block the item
+// leaves, as for Create; both blocks get a new version.
+func (r *ScheduleItemsRepo) Move(ctx context.Context, orgID, blockID, id string, blockVersion int, toBlockID string) error {
+	return r.changeBlocks(ctx, orgID, blockID, blockVersion, toBlockID, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `UPDATE schedule_items SET block_id=$3 WHERE id::text=$1 AND block_id=$2`, id, blockID, toBlockID)
+		if err == nil && tag.RowsAffected() == 0 {
+			err = ErrNotFound
+		}
+		return err
+	})
+}
+
+// changeBlocks runs change in a transaction after incrementing the version of the block
+// (only if it is at version, unless that is 0) and of the block other, if set. Both
+// must be live blocks of the organization. Their revisions are recorded around the change.
+func (r *ScheduleItemsRepo) changeBlocks(ctx context.Context, orgID, blockID string, version int, other string, change func(pgx.Tx) error) error {
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollbackTx(tx)
+	type changed struct {
+		id      string
+		version int
+	}
+	blocks := []changed{{blockID, version}}
+	if other != "" {
+		blocks = append(blocks, changed{other, 0})
+	}
+	for _, b := range blocks {
+		if err := blockRevisions.record(ctx, tx, b.id, false); err != nil {
+			return err
+		}
+		var updated string
+		row := tx.QueryRow(ctx, `
+			UPDATE blocks SET version=version+1
+			WHERE id::text=$1 AND deleted_at IS NULL AND ($3 = 0 OR version = $3)
+			  AND day_id IN (SELECT d.id FROM days d JOIN events e ON e.id = d.event_id WHERE e.organization_id = $2 AND d.deleted_at IS NULL)
+			RETURNING id::text
+		`, b.id, orgID, b.version)
+		if err := scanOne(ctx, row, &updated, func() error { return row.Scan(&updated) }); err != nil {
+			if errors.Is(err, ErrNotFound) {
+				err = missedUpdate(ctx, tx, b.version, `SELECT EXISTS (SELECT 1 FROM blocks WHERE id::text=$1 AND deleted_at IS NULL AND day_id IN (SELECT d.id FROM days d JOIN events e ON e.id = d.event_id WHERE e.organization_id = $2 AND d.deleted_at IS NULL))`, b.id, orgID)
+			}
+			return err
+		}
+	}
+	if err := change(tx); err != nil {
+		return err
+	}
+	for _, b := range blocks {
+		if err := blockRevisions.record(ctx, tx, b.id, true); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
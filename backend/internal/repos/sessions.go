@@ -0,0 +1,76 @@
+package repos
+
+import (
+	"context"
+	"time"
+
+	"planning-system/backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Session is the stored form of an issued access/refresh token pair.
+type Session struct {
+	ID               string
+	UserID           string
+	AccessTokenHash  string
+	RefreshTokenHash string
+	AccessExpiresAt  time.Time
+	RefreshExpiresAt time.Time
+}
+
+type SessionsRepo struct{ RepoBase }
+
+func NewSessionsRepo(pool *pgxpool.Pool) *SessionsRepo {
+	return &SessionsRepo{RepoBase{Pool: pool}}
+}
+
+func (r *SessionsRepo) Create(ctx context.Context, in Session) (Session, error) {
+	if in.ID == "" {
+		in.ID = uuid.NewString()
+	}
+	_, err := r.Pool.Exec(ctx, `
+		INSERT INTO sessions (id, user_id, access_token_hash, refresh_token_hash, access_expires_at, refresh_expires_at)
+		VALUES ($1,$2,$3,$4,$5,$6)
+	`, in.ID, in.UserID, in.AccessTokenHash, in.RefreshTokenHash, in.AccessExpiresAt, in.RefreshExpiresAt)
+	return in, err
+}
+
+// UserByAccessToken returns the user of a live (unrevoked, unexpired) session.
+func (r *SessionsRepo) UserByAccessToken(ctx context.Context, accessHash string) (models.User, error) {
+	var u models.User
+	row := r.Pool.QueryRow(ctx, `
+		SELECT u.id, u.organization_id, u.email, u.name, u.password_hash, u.role, u.participant_id::text
+		FROM sessions s JOIN users u ON u.id = s.user_id
+		WHERE s.access_token_hash = $1 AND s.revoked_at IS NULL AND s.access_expires_at > now()
+	`, accessHash)
+	err := scanOne(ctx, row, &u, func() error { return scanUser(row, &u) })
+	return u, err
+}
+
+// Rotate replaces the token pair of the live session identified by refreshHash,
+// so that every refresh token can be used only once. It returns the session's user ID.
+func (r *SessionsRepo) Rotate(ctx context.Context, refreshHash string, next Session) (string, error) {
+	var userID string
+	row := r.Pool.QueryRow(ctx, `
+		UPDATE sessions
+		SET access_token_hash=$2, refresh_token_hash=$3, access_expires_at=$4, refresh_expires_at=$5
+		WHERE refresh_token_hash=$1 AND revoked_at IS NULL AND refresh_expires_at > now()
+		RETURNING user_id::text
+	`, refreshHash, next.AccessTokenHash, next.RefreshTokenHash, next.AccessExpiresAt, next.RefreshExpiresAt)
+	err := scanOne(ctx, row, &userID, func() error { return row.Scan(&userID) })
+	return userID, err
+}
+
+// RevokeByAccessToken ends the session the access token belongs to.
+func (r *SessionsRepo) RevokeByAccessToken(ctx context.Context, accessHash string) error {
+	_, err := r.Pool.Exec(ctx, `UPDATE sessions SET revoked_at = now() WHERE access_token_hash=$1 AND revoked_at IS NULL`, accessHash)
+	return err
+}
+
+// DeleteStale removes the user's sessions that can no longer be used.
+func (r *SessionsRepo) DeleteStale(ctx context.Context, userID string) error {
+	_, err := r.Pool.Exec(ctx, `DELETE FROM sessions WHERE user_id=$1 AND (revoked_at IS NOT NULL OR refresh_expires_at <= now())`, userID)
+	return err
+}
@@ -0,0 +1,157 @@
+package repos
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"planning-system/backend/internal/models"
+)
+
+// dayClock is what is needed to turn a day's wall-clock "HH:mm" values into instants:
+// the day's date and the time zone of its event.
+type dayClock struct {
+	Date string
+	Loc  *time.Location
+}
+
+// dayClocks loads the date and event time zone of each given day.
+func (b RepoBase) dayClocks(ctx context.Context, dayIDs []string) (map[string]dayClock, error) {
+	out := map[string]dayClock{}
+	if len(dayIDs) == 0 {
+		return out, nil
+	}
+	rows, err := b.Pool.Query(ctx, `
+		SELECT d.id, to_char(d.date,'YYYY-MM-DD'), e.time_zone
+		FROM days d JOIN events e ON e.id = d.event_id
+		WHERE d.id = ANY($1::uuid[])
+	`, dayIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, date, tz string
+		if err := rows.Scan(&id, &date, &tz); err != nil {
+			return nil, err
+		}
+		out[id] = dayClock{Date: date, Loc: LoadLocation(tz)}
+	}
+	return out, rows.Err()
+}
+
+// LoadLocation resolves an IANA time zone name, falling back to UTC for unknown names.
+func LoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// at returns the instant of the wall-clock time "HH:mm" on the day, or nil if clock is empty or invalid.
+func (c dayClock) at(clock string) *time.Time {
+	if clock == "" || c.Loc == nil {
+		return nil
+	}
+	t, err := time.ParseInLocation("2006-01-02 15:04", c.Date+" "+clock, c.Loc)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// atOffset is at on the date dayOffset days after the day (times past midnight).
+func (c dayClock) atOffset(clock string, dayOffset int) *time.Time {
+	t := c.at(clock)
+	if t == nil || dayOffset == 0 {
+		return t
+	}
+	shifted := t.AddDate(0, 0, dayOffset)
+	return &shifted
+}
+
+func (c dayClock) setBlockTimes(b *models.Block) {
+	b.StartAt = c.at(b.StartTime)
+	b.EndAt = c.atOffset(b.EndTime, b.EndDayOffset)
+	for i := range b.ScheduleItems {
+		b.ScheduleItems[i].At = c.atOffset(b.ScheduleItems[i].Time, b.ScheduleItems[i].DayOffset)
+	}
+}
+
+func (c dayClock) setMovementTimes(m *models.Movement) {
+	m.FromAt = c.at(m.FromTime)
+	if m.ToTimeType == "fixed" {
+		m.ToAt = c.atOffset(m.ToTime, m.ToDayOffset)
+		return
+	}
+	if m.FromAt == nil {
+		return
+	}
+	mins, err := strconv.Atoi(m.ToTime)
+	if err != nil {
+		mins = 0
+		if m.DrivingTimeHours != nil {
+			mins += *m.DrivingTimeHours * 60
+		}
+		if m.DrivingTimeMinutes != nil {
+			mins += *m.DrivingTimeMinutes
+		}
+	}
+	to := m.FromAt.Add(time.Duration(mins) * time.Minute)
+	m.ToAt = &to
+}
+
+// localizeBlocks fills the absolute start/end instants of blocks and their schedule items.
+func (b RepoBase) localizeBlocks(ctx context.Context, blocks []models.Block) error {
+	dayIDs := make([]string, 0, len(blocks))
+	for _, blk := range blocks {
+		dayIDs = append(dayIDs, blk.DayID)
+	}
+	clocks, err := b.dayClocks(ctx, dayIDs)
+	if err != nil {
+		return err
+	}
+	for i := range blocks {
+		if c, ok := clocks[blocks[i].DayID]; ok {
+			c.setBlockTimes(&blocks[i])
+		}
+	}
+	return nil
+}
+
+// localizeMovements fills the absolute departure/arrival instants of movements.
+func (b RepoBase) localizeMovements(ctx context.Context, movements []models.Movement) error {
+	dayIDs := make([]string, 0, len(movements))
+	for _, m := range movements {
+		dayIDs = append(dayIDs, m.DayID)
+	}
+	clocks, err := b.dayClocks(ctx, dayIDs)
+	if err != nil {
+		return err
+	}
+	for i := range movements {
+		if c, ok := clocks[movements[i].DayID]; ok {
+			c.setMovementTimes(&movements[i])
+		}
+	}
+	return nil
+}
+
+func (b RepoBase) localizeBlock(ctx context.Context, blk *models.Block) error {
+	one := []models.Block{*blk}
+	if err := b.localizeBlocks(ctx, one); err != nil {
+		return err
+	}
+	*blk = one[0]
+	return nil
+}
+
+func (b RepoBase) localizeMovement(ctx context.Context, m *models.Movement) error {
+	one := []models.Movement{*m}
+	if err := b.localizeMovements(ctx, one); err != nil {
+		return err
+	}
+	*m = one[0]
+	return nil
+}
@@ -0,0 +1,175 @@
+package repos
+
+import (
+	"context"
+	"errors"
+
+	"planning-system/backend/internal/models"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TrashRepo lists, restores and purges soft-deleted rows. Deleting a block, movement,
+// day, participant, location or vehicle only sets its deleted_at; child rows such as
+// schedule items, participant lists and vehicle assignments are never touched, so
+// restoring the row brings them back as they were.
+type TrashRepo struct{ RepoBase }
+
+func NewTrashRepo(pool *pgxpool.Pool) *TrashRepo {
+	return &TrashRepo{RepoBase{Pool: pool}}
+}
+
+// ErrParentDeleted is returned when restoring a block or movement whose day is in the trash too.
+var ErrParentDeleted = errors.New("parent is deleted")
+
+// ErrDateTaken is returned when restoring a day whose date now belongs to another day of the event.
+var ErrDateTaken = errors.New("date is taken")
+
+// dayOfOrganization restricts blocks and movements (as t) to the organization in $2.
+const dayOfOrganization = "t.day_id IN (SELECT d.id FROM days d JOIN events e ON e.id = d.event_id WHERE e.organization_id = $2)"
+
+// trashTables maps each entity type that is soft deleted to its table and to the
+// condition restricting the table (as t) to the organization in $2.
+var trashTables = map[string]struct{ table, scope string }{
+	"location":    {"locations", "t.organization_id = $2"},
+	"vehicle":     {"vehicles", "t.organization_id = $2"},
+	"participant": {"participants", "t.organization_id = $2"},
+	"day":         {"days", "t.event_id IN (SELECT id FROM events WHERE organization_id = $2)"},
+	"block":       {"blocks", dayOfOrganization},
+	"movement":    {"movements", dayOfOrganization},
+}
+
+// trashed selects every row in the trash of the organization in $1.
+const trashed = `
+	SELECT 'location' AS type, l.id::text AS id, l.name AS label, NULL::text AS event_id, NULL::text AS day_id, NULL::text AS date, l.deleted_at
+	FROM locations l WHERE l.organization_id = $1 AND l.deleted_at IS NOT NULL
+	UNION ALL
+	SELECT 'vehicle', v.id::text, v.label, NULL, NULL, NULL, v.deleted_at
+	FROM vehicles v WHERE v.organization_id = $1 AND v.deleted_at IS NOT NULL
+	UNION ALL
+	SELECT 'participant', p.id::text, p.name, NULL, NULL, NULL, p.deleted_at
+	FROM participants p WHERE p.organization_id = $1 AND p.deleted_at IS NOT NULL
+	UNION ALL
+	SELECT 'day', d.id::text, e.name, d.event_id::text, NULL, to_char(d.date,'YYYY-MM-DD'), d.deleted_at
+	FROM days d JOIN events e ON e.id = d.event_id
+	WHERE e.organization_id = $1 AND d.deleted_at IS NOT NULL
+	UNION ALL
+	SELECT 'block', b.id::text, b.title, d.event_id::text, d.id::text, to_char(d.date,'YYYY-MM-DD'), b.deleted_at
+	FROM blocks b JOIN days d ON d.id = b.day_id JOIN events e ON e.id = d.event_id
+	WHERE e.organization_id = $1 AND b.deleted_at IS NOT NULL
+	UNION ALL
+	SELECT 'movement', m.id::text, m.title, d.event_id::text, d.id::text, to_char(d.date,'YYYY-MM-DD'), m.deleted_at
+	FROM movements m JOIN days d ON d.id = m.day_id JOIN events e ON e.id = d.event_id
+	WHERE e.organization_id = $1 AND m.deleted_at IS NOT NULL
+`
+
+// List returns the trashed rows of the given types, most recently deleted first, and their
+// total count. If memberUserID is set, days, blocks and movements are limited to the
+// events that user is a member of.
+func (r *TrashRepo) List(ctx context.Context, orgID string, types []string, memberUserID string, p PageParams) ([]models.TrashItem, int64, error) {
+	where := `
+		WHERE type = ANY($2)
+		  AND ($3 = '' OR event_id IS NULL OR event_id IN (SELECT event_id::text FROM event_members WHERE user_id = NULLIF($3,'')::uuid))
+	`
+	rows, err := r.Pool.Query(ctx, `
+		SELECT type, id, label, COALESCE(event_id,''), COALESCE(day_id,''), COALESCE(date,''), deleted_at
+		FROM (`+trashed+`) t
+	`+where+`
+		ORDER BY deleted_at DESC, id
+		LIMIT $4 OFFSET $5
+	`, orgID, types, memberUserID, p.Limit, p.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	items := make([]models.TrashItem, 0)
+	for rows.Next() {
+		var it models.TrashItem
+		if err := rows.Scan(&it.Type, &it.ID, &it.Label, &it.EventID, &it.DayID, &it.Date, &it.DeletedAt); err != nil {
+			return nil, 0, err
+		}
+		items = append(items, it)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	var total int64
+	if err := r.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM (`+trashed+`) t`+where, orgID, types, memberUserID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// Get returns a single trashed row.
+func (r *TrashRepo) Get(ctx context.Context, orgID, typ, id string) (models.TrashItem, error) {
+	var it models.TrashItem
+	row := r.Pool.QueryRow(ctx, `
+		SELECT type, id, label, COALESCE(event_id,''), COALESCE(day_id,''), COALESCE(date,''), deleted_at
+		FROM (`+trashed+`) t
+		WHERE type = $2 AND id = $3
+	`, orgID, typ, id)
+	err := scanOne(ctx, row, &it, func() error {
+		return row.Scan(&it.Type, &it.ID, &it.Label, &it.EventID, &it.DayID, &it.Date, &it.DeletedAt)
+	})
+	return it, err
+}
+
+// Restore takes the row out of the trash. A block or movement can only be restored
+// once its day is, and a day only while no other day of the event has its date.
+func (r *TrashRepo) Restore(ctx context.Context, orgID, typ, id string) error {
+	t, ok := trashTables[typ]
+	if !ok {
+		return ErrNotFound
+	}
+	tx, err := r.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollbackTx(tx)
+	tag, err := tx.Exec(ctx, `
+		UPDATE `+t.table+` t SET deleted_at = NULL
+		WHERE t.id::text = $1 AND t.deleted_at IS NOT NULL AND `+t.scope, id, orgID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrDateTaken
+		}
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	if typ == "block" || typ == "movement" {
+		var dayDeleted bool
+		if err := tx.QueryRow(ctx, `
+			SELECT d.deleted_at IS NOT NULL FROM days d JOIN `+t.table+` t ON t.day_id = d.id
+			WHERE t.id::text = $1
+		`, id).Scan(&dayDeleted); err != nil {
+			return err
+		}
+		if dayDeleted {
+			return ErrParentDeleted
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// Purge permanently deletes a trashed row. Child rows go with it through ON DELETE
+// CASCADE; a row that is still referenced fails with a foreign key violation.
+func (r *TrashRepo) Purge(ctx context.Context, orgID, typ, id string) error {
+	t, ok := trashTables[typ]
+	if !ok {
+		return ErrNotFound
+	}
+	tag, err := r.Pool.Exec(ctx, `
+		DELETE FROM `+t.table+` t
+		WHERE t.id::text = $1 AND t.deleted_at IS NOT NULL AND `+t.scope, id, orgID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package repos
+
+import (
+	"context"
+
+	"planning-system/backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type UsersRepo struct{ RepoBase }
+
+func NewUsersRepo(pool *pgxpool.Pool) *UsersRepo {
+	return &UsersRepo{RepoBase{Pool: pool}}
+}
+
+const userColumns = `id, organization_id, email, name, password_hash, role, participant_id::text`
+
+func scanUser(row interface{ Scan(...any) error }, u *models.User) error {
+	return row.Scan(&u.ID, &u.OrganizationID, &u.Email, &u.Name, &u.PasswordHash, &u.Role, &u.ParticipantID)
+}
+
+// GetByEmail looks a user up by email, case-insensitively.
+func (r *UsersRepo) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	var u models.User
+	row := r.Pool.QueryRow(ctx, `SELECT `+userColumns+` FROM users WHERE lower(email) = lower($1)`, email)
+	err := scanOne(ctx, row, &u, func() error { return scanUser(row, &u) })
+	return u, err
+}
+
+func (r *UsersRepo) Get(ctx context.Context, id string) (models.User, error) {
+	var u models.User
+	row := r.Pool.QueryRow(ctx, `SELECT `+userColumns+` FROM users WHERE id = $1`, id)
+	err := scanOne(ctx, row, &u, func() error { return scanUser(row, &u) })
+	return u, err
+}
+
+// Create inserts a user; in.PasswordHash must already be hashed.
+func (r *UsersRepo) Create(ctx context.Context, in models.User) (models.User, error) {
+	if in.ID == "" {
+		in.ID = uuid.NewString()
+	}
+	_, err := r.Pool.Exec(ctx, `
+		INSERT INTO users (id, organization_id, email, name, password_hash, role, participant_id)
+		VALUES ($1,$2,$3,$4,$5,$6,NULLIF($7,'')::uuid)
+	`, in.ID, in.OrganizationID, in.Email, in.Name, in.PasswordHash, in.Role, nullableString(in.ParticipantID))
+	return in, err
+}
+
+// GetByOIDCSubject returns the user linked to an identity provider subject ("issuer|sub").
+func (r *UsersRepo) GetByOIDCSubject(ctx context.Context, subject string) (models.User, error) {
+	var u models.User
+	row := r.Pool.QueryRow(ctx, `SELECT `+userColumns+` FROM users WHERE oidc_subject = $1`, subject)
+	err := scanOne(ctx, row, &u, func() error { return scanUser(row, &u) })
+	return u, err
+}
+
+// LinkOIDCSubject links the user to an identity provider subject.
+func (r *UsersRepo) LinkOIDCSubject(ctx context.Context, id, subject string) error {
+	_, err := r.Pool.Exec(ctx, `UPDATE users SET oidc_subject = $2 WHERE id = $1`, id, subject)
+	return err
+}
+
+func (r *UsersRepo) SetRole(ctx context.Context, id, role string) error {
+	_, err := r.Pool.Exec(ctx, `UPDATE users SET role = $2 WHERE id = $1`, id, role)
+	return err
+}
@@ -2,6 +2,7 @@ package repos
 
 import (
 	"context"
+	"errors"
 
 	"planning-system/backend/internal/models"
 
@@ -15,13 +16,14 @@ func NewVehiclesRepo(pool *pgxpool.Pool) *VehiclesRepo {
 	return &VehiclesRepo{RepoBase{Pool: pool}}
 }
 
-func (r *VehiclesRepo) List(ctx context.Context) ([]models.Vehicle, error) {
+func (r *VehiclesRepo) List(ctx context.Context, orgID string) ([]models.Vehicle, error) {
 	rows, err := r.Pool.Query(ctx, `
 		SELECT id, label, COALESCE(make,''), COALESCE(model,''), COALESCE(license_plate,''), capacity, COALESCE(notes,''),
-		       to_char(available_from,'HH24:MI'), to_char(available_to,'HH24:MI'), origination_location_id::text
+		       to_char(available_from,'HH24:MI'), to_char(available_to,'HH24:MI'), origination_location_id::text, version
 		FROM vehicles
+		WHERE organization_id = $1 AND deleted_at IS NULL
 		ORDER BY label ASC
-	`)
+	`, orgID)
 	if err != nil {
 		return nil, err
 	}
@@ -31,7 +33,7 @@ func (r *VehiclesRepo) List(ctx context.Context) ([]models.Vehicle, error) {
 		var m models.Vehicle
 		var capacity *int
 		var availableFrom, availableTo, originationLocationID *string
-		if err := rows.Scan(&m.ID, &m.Label, &m.Make, &m.Model, &m.LicensePlate, &capacity, &m.Notes, &availableFrom, &availableTo, &originationLocationID); err != nil {
+		if err := rows.Scan(&m.ID, &m.Label, &m.Make, &m.Model, &m.LicensePlate, &capacity, &m.Notes, &availableFrom, &availableTo, &originationLocationID, &m.Version); err != nil {
 			return nil, err
 		}
 		m.Capacity = capacity
@@ -43,20 +45,17 @@ func (r *VehiclesRepo) List(ctx context.Context) ([]models.Vehicle, error) {
 	return items, rows.Err()
 }
 
-func (r *VehiclesRepo) Get(ctx context.Context, id string) (models.Vehicle, error) {
+func (r *VehiclesRepo) Get(ctx context.Context, orgID, id string) (models.Vehicle, error) {
 	var m models.Vehicle
 	var capacity *int
 	var availableFrom, availableTo, originationLocationID *string
-	err := scanOne(ctx, r.Pool.QueryRow(ctx, `
+	row := r.Pool.QueryRow(ctx, `
 		SELECT id, label, COALESCE(make,''), COALESCE(model,''), COALESCE(license_plate,''), capacity, COALESCE(notes,''),
-		       to_char(available_from,'HH24:MI'), to_char(available_to,'HH24:MI'), origination_location_id::text
-		FROM vehicles WHERE id = $1
-	`, id), &m, func() error {
-		return r.Pool.QueryRow(ctx, `
-			SELECT id, label, COALESCE(make,''), COALESCE(model,''), COALESCE(license_plate,''), capacity, COALESCE(notes,''),
-			       to_char(available_from,'HH24:MI'), to_char(available_to,'HH24:MI'), origination_location_id::text
-			FROM vehicles WHERE id = $1
-		`, id).Scan(&m.ID, &m.Label, &m.Make, &m.Model, &m.LicensePlate, &capacity, &m.Notes, &availableFrom, &availableTo, &originationLocationID)
+		       to_char(available_from,'HH24:MI'), to_char(available_to,'HH24:MI'), origination_location_id::text, version
+		FROM vehicles WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL
+	`, id, orgID)
+	err := scanOne(ctx, row, &m, func() error {
+		return row.Scan(&m.ID, &m.Label, &m.Make, &m.Model, &m.LicensePlate, &capacity, &m.Notes, &availableFrom, &availableTo, &originationLocationID, &m.Version)
 	})
 	if err == nil {
 		m.Capacity = capacity
@@ -67,7 +66,7 @@ func (r *VehiclesRepo) Get(ctx context.Context, id string) (models.Vehicle, erro
 	return m, err
 }
 
-func (r *VehiclesRepo) Create(ctx context.Context, in models.Vehicle) (models.Vehicle, error) {
+func (r *VehiclesRepo) Create(ctx context.Context, orgID string, in models.Vehicle) (models.Vehicle, error) {
 	if in.ID == "" {
 		in.ID = uuid.NewString()
 	}
@@ -83,13 +82,16 @@ func (r *VehiclesRepo) Create(ctx context.Context, in models.Vehicle) (models.Ve
 		capacity = *in.Capacity
 	}
 	_, err := r.Pool.Exec(ctx, `
-		INSERT INTO vehicles (id, label, make, model, license_plate, capacity, notes, available_from, available_to, origination_location_id)
-		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
-	`, in.ID, in.Label, in.Make, in.Model, in.LicensePlate, capacity, in.Notes, availableFrom, availableTo, in.OriginationLocationID)
+		INSERT INTO vehicles (id, organization_id, label, make, model, license_plate, capacity, notes, available_from, available_to, origination_location_id)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)
+	`, in.ID, orgID, in.Label, in.Make, in.Model, in.LicensePlate, capacity, in.Notes, availableFrom, availableTo, in.OriginationLocationID)
+	in.Version = 1
 	return in, err
 }
 
-func (r *VehiclesRepo) Update(ctx context.Context, id string, in models.Vehicle) (models.Vehicle, error) {
+// Update replaces the vehicle. If in.Version is set, the update only applies to that
+// version of the row and fails with ErrVersionMismatch otherwise.
+func (r *VehiclesRepo) Update(ctx context.Context, orgID, id string, in models.Vehicle) (models.Vehicle, error) {
 	var availableFrom, availableTo interface{}
 	if in.AvailableFrom != nil && *in.AvailableFrom != "" {
 		availableFrom = *in.AvailableFrom
@@ -101,24 +103,30 @@ func (r *VehiclesRepo) Update(ctx context.Context, id string, in models.Vehicle)
 	if in.Capacity != nil {
 		capacity = *in.Capacity
 	}
-	tag, err := r.Pool.Exec(ctx, `
+	row := r.Pool.QueryRow(ctx, `
 		UPDATE vehicles
-		SET label=$2, make=$3, model=$4, license_plate=$5, capacity=$6, notes=$7, available_from=$8, available_to=$9, origination_location_id=$10
-		WHERE id=$1
-	`, id, in.Label, in.Make, in.Model, in.LicensePlate, capacity, in.Notes, availableFrom, availableTo, in.OriginationLocationID)
-	if err != nil {
+		SET label=$3, make=$4, model=$5, license_plate=$6, capacity=$7, notes=$8, available_from=$9, available_to=$10, origination_location_id=$11, version=version+1
+		WHERE id=$1 AND organization_id=$2 AND deleted_at IS NULL AND ($12 = 0 OR version = $12)
+		RETURNING version
+	`, id, orgID, in.Label, in.Make, in.Model, in.LicensePlate, capacity, in.Notes, availableFrom, availableTo, in.OriginationLocationID, in.Version)
+	if err := scanOne(ctx, row, &in, func() error { return row.Scan(&in.Version) }); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			err = missedUpdate(ctx, r.Pool, in.Version, `SELECT EXISTS (SELECT 1 FROM vehicles WHERE id=$1 AND organization_id=$2 AND deleted_at IS NULL)`, id, orgID)
+		}
 		return models.Vehicle{}, err
 	}
-	if tag.RowsAffected() == 0 {
-		return models.Vehicle{}, ErrNotFound
-	}
 	in.ID = id
 	return in, nil
 }
 
-func (r *VehiclesRepo) Delete(ctx context.Context, id string) error {
-	_, err := r.Pool.Exec(ctx, `DELETE FROM vehicles WHERE id=$1`, id)
-	return err
+// Delete moves the vehicle to the trash (see TrashRepo). If blocks, movements or other
+// records still use it, it fails with *InUseError unless opts say to replace or remove
+// those references. It returns the references found and the blocks and movements changed.
+func (r *VehiclesRepo) Delete(ctx context.Context, orgID, id string, opts DeleteOptions) ([]models.Reference, []ChangedRow, error) {
+	return r.deleteReferenced(ctx, vehicleReferences, orgID, id, opts)
 }
 
-
+// Missing returns the given IDs that are not vehicles of the organization.
+func (r *VehiclesRepo) Missing(ctx context.Context, orgID string, ids []string) ([]string, error) {
+	return missingIDs(ctx, r.Pool, "vehicles", orgID, ids)
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"planning-system/backend/internal/auth"
+	"planning-system/backend/internal/models"
+	"planning-system/backend/internal/repos"
+)
+
+// CreateAPIKey generates a new key for the organization. The key is only returned here.
+func (s *Services) CreateAPIKey(ctx context.Context, orgID, createdBy string, in models.CreateAPIKeyRequest) (models.CreatedAPIKey, error) {
+	secret, err := auth.NewToken()
+	if err != nil {
+		return models.CreatedAPIKey{}, err
+	}
+	key := auth.APIKeyPrefix + secret
+	k, err := s.APIKeys.Create(ctx, models.APIKey{
+		OrganizationID: orgID,
+		Name:           in.Name,
+		Prefix:         key[:len(auth.APIKeyPrefix)+6],
+		Scopes:         in.Scopes,
+		ExpiresAt:      in.ExpiresAt,
+	}, auth.HashToken(key), createdBy)
+	if err != nil {
+		return models.CreatedAPIKey{}, err
+	}
+	return models.CreatedAPIKey{Key: key, APIKey: k}, nil
+}
+
+// AuthenticateAPIKey resolves an API key to its live row and records its use.
+func (s *Services) AuthenticateAPIKey(ctx context.Context, key string) (models.APIKey, error) {
+	k, err := s.APIKeys.GetActiveByHash(ctx, auth.HashToken(key))
+	if err != nil {
+		if errors.Is(err, repos.ErrNotFound) {
+			return models.APIKey{}, auth.ErrInvalidToken
+		}
+		return models.APIKey{}, err
+	}
+	if err := s.APIKeys.TouchLastUsed(ctx, k.ID); err != nil {
+		return models.APIKey{}, err
+	}
+	return k, nil
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"planning-system/backend/internal/auth"
+	"planning-system/backend/internal/models"
+	"planning-system/backend/internal/repos"
+)
+
+var (
+	dummyHashOnce sync.Once
+	dummyHash     string
+)
+
+// Login checks the credentials and opens a new session.
+func (s *Services) Login(ctx context.Context, email, password string) (models.AuthTokens, error) {
+	u, err := s.Users.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, repos.ErrNotFound) {
+			// Spend the same time as a real check so unknown emails cannot be told apart
+			dummyHashOnce.Do(func() { dummyHash, _ = auth.HashPassword("not-a-real-password") })
+			auth.CheckPassword(dummyHash, password)
+			return models.AuthTokens{}, auth.ErrInvalidCredentials
+		}
+		return models.AuthTokens{}, err
+	}
+	if !auth.CheckPassword(u.PasswordHash, password) {
+		return models.AuthTokens{}, auth.ErrInvalidCredentials
+	}
+	return s.startSession(ctx, u)
+}
+
+// startSession opens a new session for an authenticated user.
+func (s *Services) startSession(ctx context.Context, u models.User) (models.AuthTokens, error) {
+	if err := s.Sessions.DeleteStale(ctx, u.ID); err != nil {
+		return models.AuthTokens{}, err
+	}
+	sess, tokens, err := s.newTokens()
+	if err != nil {
+		return models.AuthTokens{}, err
+	}
+	sess.UserID = u.ID
+	if _, err := s.Sessions.Create(ctx, sess); err != nil {
+		return models.AuthTokens{}, err
+	}
+	tokens.User = u
+	return tokens, nil
+}
+
+// Refresh exchanges a refresh token for a new token pair. The old pair stops working.
+func (s *Services) Refresh(ctx context.Context, refreshToken string) (models.AuthTokens, error) {
+	sess, tokens, err := s.newTokens()
+	if err != nil {
+		return models.AuthTokens{}, err
+	}
+	userID, err := s.Sessions.Rotate(ctx, auth.HashToken(refreshToken), sess)
+	if err != nil {
+		if errors.Is(err, repos.ErrNotFound) {
+			return models.AuthTokens{}, auth.ErrInvalidToken
+		}
+		return models.AuthTokens{}, err
+	}
+	u, err := s.Users.Get(ctx, userID)
+	if err != nil {
+		return models.AuthTokens{}, err
+	}
+	tokens.User = u
+	return tokens, nil
+}
+
+// Logout revokes the session of the given access token.
+func (s *Services) Logout(ctx context.Context, accessToken string) error {
+	return s.Sessions.RevokeByAccessToken(ctx, auth.HashToken(accessToken))
+}
+
+// Authenticate resolves an access token to its user.
+func (s *Services) Authenticate(ctx context.Context, accessToken string) (models.User, error) {
+	u, err := s.Sessions.UserByAccessToken(ctx, auth.HashToken(accessToken))
+	if errors.Is(err, repos.ErrNotFound) {
+		return models.User{}, auth.ErrInvalidToken
+	}
+	return u, err
+}
+
+// newTokens generates a fresh access/refresh token pair and its stored form.
+func (s *Services) newTokens() (repos.Session, models.AuthTokens, error) {
+	access, err := auth.NewToken()
+	if err != nil {
+		return repos.Session{}, models.AuthTokens{}, err
+	}
+	refresh, err := auth.NewToken()
+	if err != nil {
+		return repos.Session{}, models.AuthTokens{}, err
+	}
+	now := time.Now().UTC()
+	sess := repos.Session{
+		AccessTokenHash:  auth.HashToken(access),
+		RefreshTokenHash: auth.HashToken(refresh),
+		AccessExpiresAt:  now.Add(s.accessTokenTTL),
+		RefreshExpiresAt: now.Add(s.refreshTokenTTL),
+	}
+	return sess, models.AuthTokens{
+		AccessToken:      access,
+		RefreshToken:     refresh,
+		TokenType:        "Bearer",
+		ExpiresAt:        sess.AccessExpiresAt,
+		RefreshExpiresAt: sess.RefreshExpiresAt,
+	}, nil
+}
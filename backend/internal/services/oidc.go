@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"planning-system/backend/internal/auth"
+	"planning-system/backend/internal/config"
+	"planning-system/backend/internal/models"
+	"planning-system/backend/internal/oidc"
+	"planning-system/backend/internal/repos"
+)
+
+var (
+	ErrSSODisabled  = errors.New("single sign-on is not configured")
+	ErrSSONoAccount = errors.New("no account for this identity")
+)
+
+// ssoLoginTTL is how long a user has to complete the login at the identity provider.
+const ssoLoginTTL = 10 * time.Minute
+
+// rolePriority orders roles by privilege; a user in several mapped groups gets the highest.
+var rolePriority = []string{auth.RoleAdmin, auth.RolePlanner, auth.RoleStaff, auth.RoleParticipant}
+
+// sso holds the OpenID Connect settings and the lazily discovered provider.
+type sso struct {
+	cfg         oidc.Config
+	groupsClaim string
+	roleMap     map[string]string
+	defaultRole string
+	orgID       string
+	redirect    string
+
+	mu       sync.Mutex
+	provider *oidc.Provider
+}
+
+func newSSO(cfg config.Config) *sso {
+	if cfg.OIDCIssuer == "" {
+		return nil
+	}
+	return &sso{
+		cfg: oidc.Config{
+			Issuer:       cfg.OIDCIssuer,
+			ClientID:     cfg.OIDCClientID,
+			ClientSecret: cfg.OIDCClientSecret,
+			RedirectURL:  cfg.OIDCRedirectURL,
+			Scopes:       cfg.OIDCScopes,
+		},
+		groupsClaim: cfg.OIDCGroupsClaim,
+		roleMap:     cfg.OIDCRoleMap,
+		defaultRole: cfg.OIDCDefaultRole,
+		orgID:       cfg.OIDCOrganizationID,
+		redirect:    cfg.OIDCPostLoginRedirect,
+	}
+}
+
+// Provider discovers the identity provider on first use, and retries after a failure.
+func (s *sso) Provider(ctx context.Context) (*oidc.Provider, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.provider == nil {
+		p, err := oidc.Discover(ctx, s.cfg)
+		if err != nil {
+			return nil, err
+		}
+		s.provider = p
+	}
+	return s.provider, nil
+}
+
+// SSOPostLoginRedirect returns the frontend URL that receives the tokens after an SSO
+// login, or "" to answer the callback with JSON.
+func (s *Services) SSOPostLoginRedirect() string {
+	if s.sso == nil {
+		return ""
+	}
+	return s.sso.redirect
+}
+
+// ssoLoginState travels in a signed cookie between the login redirect and the callback.
+type ssoLoginState struct {
+	State    string `json:"s"`
+	Nonce    string `json:"n"`
+	Verifier string `json:"v"`
+}
+
+// BeginSSOLogin returns the identity provider URL to redirect the browser to, and
+// the signed login state to keep in a cookie until the callback.
+func (s *Services) BeginSSOLogin(ctx context.Context) (redirectURL, loginState string, err error) {
+	if s.sso == nil {
+		return "", "", ErrSSODisabled
+	}
+	p, err := s.sso.Provider(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	var st ssoLoginState
+	for _, v := range []*string{&st.State, &st.Nonce, &st.Verifier} {
+		if *v, err = auth.NewToken(); err != nil {
+			return "", "", err
+		}
+	}
+	loginState, err = auth.SignValue(s.tokenSecret, "oidc-login", st, ssoLoginTTL)
+	if err != nil {
+		return "", "", err
+	}
+	return p.AuthCodeURL(st.State, st.Nonce, st.Verifier), loginState, nil
+}
+
+// CompleteSSOLogin checks the callback against the login state, redeems the code,
+// maps the identity to a local user and opens a session.
+func (s *Services) CompleteSSOLogin(ctx context.Context, loginState, state, code string) (models.AuthTokens, error) {
+	if s.sso == nil {
+		return models.AuthTokens{}, ErrSSODisabled
+	}
+	var st ssoLoginState
+	if err := auth.OpenValue(s.tokenSecret, "oidc-login", loginState, &st); err != nil || st.State != state || code == "" {
+		return models.AuthTokens{}, auth.ErrInvalidToken
+	}
+	p, err := s.sso.Provider(ctx)
+	if err != nil {
+		return models.AuthTokens{}, err
+	}
+	claims, err := p.Exchange(ctx, code, st.Verifier, st.Nonce)
+	if err != nil {
+		return models.AuthTokens{}, err
+	}
+	u, err := s.ssoUser(ctx, claims)
+	if err != nil {
+		return models.AuthTokens{}, err
+	}
+	return s.startSession(ctx, u)
+}
+
+// ssoUser finds the local user of an identity, by linked subject first and then by
+// verified email, or creates one. Roles mapped from the groups claim replace the
+// user's role on every login; without a mapped group an existing user keeps theirs.
+func (s *Services) ssoUser(ctx context.Context, c oidc.Claims) (models.User, error) {
+	subject := c.Issuer + "|" + c.Subject
+	role := s.sso.mappedRole(c.Strings(s.sso.groupsClaim))
+
+	u, err := s.Users.GetByOIDCSubject(ctx, subject)
+	if errors.Is(err, repos.ErrNotFound) && c.Email != "" && c.EmailVerified {
+		if u, err = s.Users.GetByEmail(ctx, c.Email); err == nil {
+			err = s.Users.LinkOIDCSubject(ctx, u.ID, subject)
+		}
+	}
+	switch {
+	case err == nil:
+		if role != "" && role != u.Role {
+			if err := s.Users.SetRole(ctx, u.ID, role); err != nil {
+				return models.User{}, err
+			}
+			u.Role = role
+		}
+		return u, nil
+	case !errors.Is(err, repos.ErrNotFound):
+		return models.User{}, err
+	}
+
+	if role == "" {
+		role = s.sso.defaultRole
+	}
+	if role == "" || s.sso.orgID == "" || c.Email == "" || !c.EmailVerified {
+		return models.User{}, ErrSSONoAccount
+	}
+	name := c.Name
+	if name == "" {
+		name = strings.Split(c.Email, "@")[0]
+	}
+	u, err = s.Users.Create(ctx, models.User{OrganizationID: s.sso.orgID, Email: c.Email, Name: name, Role: role})
+	if err != nil {
+		return models.User{}, err
+	}
+	if err := s.Users.LinkOIDCSubject(ctx, u.ID, subject); err != nil {
+		return models.User{}, err
+	}
+	return u, nil
+}
+
+// mappedRole returns the most privileged role mapped from the groups, or "".
+func (s *sso) mappedRole(groups []string) string {
+	have := map[string]bool{}
+	for _, g := range groups {
+		if role := s.roleMap[g]; auth.ValidRole(role) {
+			have[role] = true
+		}
+	}
+	for _, role := range rolePriority {
+		if have[role] {
+			return role
+		}
+	}
+	return ""
+}
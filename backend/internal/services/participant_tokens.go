@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"planning-system/backend/internal/auth"
+	"planning-system/backend/internal/models"
+	"planning-system/backend/internal/repos"
+)
+
+// DefaultAccessTokenHours is the lifetime of a participant access token when none is requested.
+const DefaultAccessTokenHours = 30 * 24
+
+// maxAccessTokenHours caps the lifetime of participant access tokens at one year.
+const maxAccessTokenHours = 365 * 24
+
+// IssueParticipantToken creates a magic-link token for the participant's agenda.
+func (s *Services) IssueParticipantToken(ctx context.Context, orgID, participantID, createdBy string, in models.IssueAccessTokenRequest) (models.IssuedAccessToken, error) {
+	if _, err := s.Participants.Get(ctx, orgID, participantID); err != nil {
+		return models.IssuedAccessToken{}, err
+	}
+	hours := in.ExpiresInHours
+	if hours == 0 {
+		hours = DefaultAccessTokenHours
+	}
+	t, err := s.ParticipantTokens.Create(ctx, models.ParticipantAccessToken{
+		OrganizationID: orgID,
+		ParticipantID:  participantID,
+		Label:          in.Label,
+		ExpiresAt:      time.Now().UTC().Add(time.Duration(hours) * time.Hour).Truncate(time.Second),
+	}, createdBy)
+	if err != nil {
+		return models.IssuedAccessToken{}, err
+	}
+	token, err := auth.SignParticipantToken(s.tokenSecret, t.ID, t.ExpiresAt)
+	if err != nil {
+		return models.IssuedAccessToken{}, err
+	}
+	return models.IssuedAccessToken{
+		Token:       token,
+		AgendaPath:  "/agenda/" + participantID + "?token=" + url.QueryEscape(token),
+		AccessToken: t,
+	}, nil
+}
+
+// AuthenticateParticipantToken verifies a magic-link token and returns its live row.
+func (s *Services) AuthenticateParticipantToken(ctx context.Context, token string) (models.ParticipantAccessToken, error) {
+	id, err := auth.ParseParticipantToken(s.tokenSecret, token)
+	if err != nil {
+		return models.ParticipantAccessToken{}, err
+	}
+	t, err := s.ParticipantTokens.GetActive(ctx, id)
+	if errors.Is(err, repos.ErrNotFound) {
+		return models.ParticipantAccessToken{}, auth.ErrInvalidToken
+	}
+	return t, err
+}
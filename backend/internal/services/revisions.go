@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"planning-system/backend/internal/models"
+)
+
+// revisionLists are the list fields of a snapshot that are compared item by item, with
+// the field identifying an item, or "" to match items by position. Vehicle assignments
+// have no ID of their own (they are written anew on every update) and a vehicle may be
+// assigned twice, so they are matched by position.
+var revisionLists = map[string]string{
+	"scheduleItems":      "id",
+	"vehicleAssignments": "",
+}
+
+// DiffRevisions compares two revisions of a block or movement.
+func (s *Services) DiffRevisions(ctx context.Context, orgID, typ, id string, from, to int) (models.RevisionDiff, error) {
+	a, err := s.Revisions.Get(ctx, orgID, typ, id, from)
+	if err != nil {
+		return models.RevisionDiff{}, err
+	}
+	b, err := s.Revisions.Get(ctx, orgID, typ, id, to)
+	if err != nil {
+		return models.RevisionDiff{}, err
+	}
+	var before, after map[string]any
+	if err := json.Unmarshal(a.Snapshot, &before); err != nil {
+		return models.RevisionDiff{}, err
+	}
+	if err := json.Unmarshal(b.Snapshot, &after); err != nil {
+		return models.RevisionDiff{}, err
+	}
+	d := models.RevisionDiff{From: from, To: to, Changes: []models.FieldChange{}}
+	for _, field := range unionKeys(before, after) {
+		if field == "version" {
+			continue
+		}
+		if key, ok := revisionLists[field]; ok {
+			d.Changes = append(d.Changes, diffList(field, key, before[field], after[field])...)
+			continue
+		}
+		if !reflect.DeepEqual(before[field], after[field]) {
+			d.Changes = append(d.Changes, models.FieldChange{Field: field, From: before[field], To: after[field]})
+		}
+	}
+	return d, nil
+}
+
+// diffList reports the items of a list that were added, removed or changed, matching
+// them by their key field, or by position if key is "". Changed items are reported
+// field by field.
+func diffList(field, key string, before, after any) []models.FieldChange {
+	index := func(v any) ([]string, map[string]map[string]any) {
+		list, _ := v.([]any)
+		keys := make([]string, 0, len(list))
+		items := make(map[string]map[string]any, len(list))
+		for i, el := range list {
+			item, _ := el.(map[string]any)
+			k := strconv.Itoa(i)
+			if key != "" {
+				k = fmt.Sprint(item[key])
+			}
+			if _, dup := items[k]; !dup {
+				keys = append(keys, k)
+			}
+			items[k] = item
+		}
+		return keys, items
+	}
+	beforeKeys, beforeItems := index(before)
+	afterKeys, afterItems := index(after)
+	var changes []models.FieldChange
+	for _, k := range beforeKeys {
+		path := field + "[" + k + "]"
+		a, b := beforeItems[k], afterItems[k]
+		if b == nil {
+			changes = append(changes, models.FieldChange{Field: path, From: a, To: nil})
+			continue
+		}
+		for _, f := range unionKeys(a, b) {
+			if !reflect.DeepEqual(a[f], b[f]) {
+				changes = append(changes, models.FieldChange{Field: path + "." + f, From: a[f], To: b[f]})
+			}
+		}
+	}
+	for _, k := range afterKeys {
+		if beforeItems[k] == nil {
+			changes = append(changes, models.FieldChange{Field: field + "[" + k + "]", From: nil, To: afterItems[k]})
+		}
+	}
+	return changes
+}
+
+func unionKeys(a, b map[string]any) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,30 +1,67 @@
 package services
 
 import (
+	"time"
+
 	"github.com/jackc/pgx/v5/pgxpool"
+	"planning-system/backend/internal/config"
 	"planning-system/backend/internal/repos"
 )
 
 type Services struct {
-	Locations    *repos.LocationsRepo
-	Vehicles     *repos.VehiclesRepo
-	Participants *repos.ParticipantsRepo
-	Days         *repos.DaysRepo
-	Blocks       *repos.BlocksRepo
-	Movements    *repos.MovementsRepo
-	Itinerary    *repos.ItineraryRepo
+	Organizations     *repos.OrganizationsRepo
+	Users             *repos.UsersRepo
+	Sessions          *repos.SessionsRepo
+	Events            *repos.EventsRepo
+	EventMembers      *repos.EventMembersRepo
+	Locations         *repos.LocationsRepo
+	Vehicles          *repos.VehiclesRepo
+	Participants      *repos.ParticipantsRepo
+	ParticipantTokens *repos.ParticipantTokensRepo
+	APIKeys           *repos.APIKeysRepo
+	Days              *repos.DaysRepo
+	Blocks            *repos.BlocksRepo
+	Movements         *repos.MovementsRepo
+	BlockTemplates    *repos.BlockTemplatesRepo
+	Itinerary         *repos.ItineraryRepo
+	Audit             *repos.AuditRepo
+	Trash             *repos.TrashRepo
+	Idempotency       *repos.IdempotencyRepo
+	Revisions         *repos.RevisionsRepo
+	ScheduleItems     *repos.ScheduleItemsRepo
+
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+	tokenSecret     []byte
+	sso             *sso
 }
 
-func New(pool *pgxpool.Pool) *Services {
+func New(pool *pgxpool.Pool, cfg config.Config) *Services {
 	return &Services{
-		Locations:    repos.NewLocationsRepo(pool),
-		Vehicles:     repos.NewVehiclesRepo(pool),
-		Participants: repos.NewParticipantsRepo(pool),
-		Days:         repos.NewDaysRepo(pool),
-		Blocks:       repos.NewBlocksRepo(pool),
-		Movements:    repos.NewMovementsRepo(pool),
-		Itinerary:    repos.NewItineraryRepo(pool),
+		Organizations:     repos.NewOrganizationsRepo(pool),
+		Users:             repos.NewUsersRepo(pool),
+		Sessions:          repos.NewSessionsRepo(pool),
+		Events:            repos.NewEventsRepo(pool),
+		EventMembers:      repos.NewEventMembersRepo(pool),
+		Locations:         repos.NewLocationsRepo(pool),
+		Vehicles:          repos.NewVehiclesRepo(pool),
+		Participants:      repos.NewParticipantsRepo(pool),
+		ParticipantTokens: repos.NewParticipantTokensRepo(pool),
+		APIKeys:           repos.NewAPIKeysRepo(pool),
+		Days:              repos.NewDaysRepo(pool),
+		Blocks:            repos.NewBlocksRepo(pool),
+		Movements:         repos.NewMovementsRepo(pool),
+		BlockTemplates:    repos.NewBlockTemplatesRepo(pool),
+		Itinerary:         repos.NewItineraryRepo(pool),
+		Audit:             repos.NewAuditRepo(pool),
+		Trash:             repos.NewTrashRepo(pool),
+		Idempotency:       repos.NewIdempotencyRepo(pool),
+		Revisions:         repos.NewRevisionsRepo(pool),
+		ScheduleItems:     repos.NewScheduleItemsRepo(pool),
+
+		accessTokenTTL:  cfg.AccessTokenTTL,
+		refreshTokenTTL: cfg.RefreshTokenTTL,
+		tokenSecret:     []byte(cfg.TokenSecret),
+		sso:             newSSO(cfg),
 	}
 }
-
-
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"planning-system/backend/internal/models"
+)
+
+// SaveBlockAsTemplate stores an existing block and its schedule items as a named
+// template. Schedule item times become offsets from the block start; participant
+// assignments are not part of a template.
+func (s *Services) SaveBlockAsTemplate(ctx context.Context, orgID, dayID, blockID, name string) (models.BlockTemplate, error) {
+	b, err := s.Blocks.Get(ctx, orgID, dayID, blockID)
+	if err != nil {
+		return models.BlockTemplate{}, err
+	}
+	start, err := clockMinutes(b.StartTime)
+	if err != nil {
+		return models.BlockTemplate{}, err
+	}
+	t := models.BlockTemplate{
+		Name:         name,
+		Type:         b.Type,
+		Title:        b.Title,
+		Description:  b.Description,
+		EndTimeFixed: b.EndTimeFixed,
+		LocationID:   b.LocationID,
+		Notes:        b.Notes,
+	}
+	if b.EndTime != "" {
+		end, err := clockMinutes(b.EndTime)
+		if err != nil {
+			return models.BlockTemplate{}, err
+		}
+		duration := end + b.EndDayOffset*minutesPerDay - start
+		t.DurationMinutes = &duration
+	}
+	for _, si := range b.ScheduleItems {
+		at, err := clockMinutes(si.Time)
+		if err != nil {
+			return models.BlockTemplate{}, err
+		}
+		t.Items = append(t.Items, models.BlockTemplateItem{
+			OffsetMinutes:     at + si.DayOffset*minutesPerDay - start,
+			Description:       si.Description,
+			StaffInstructions: si.StaffInstructions,
+			GuestInstructions: si.GuestInstructions,
+			Notes:             si.Notes,
+		})
+	}
+	return s.BlockTemplates.Create(ctx, orgID, t)
+}
+
+// InstantiateBlockTemplate creates a block on the given day from a template, with
+// the block and its schedule items anchored at startTime (HH:mm). The block is
+// validated like any other, so a template whose location has since been deleted
+// fails with *ValidationError.
+func (s *Services) InstantiateBlockTemplate(ctx context.Context, orgID, dayID string, in models.InstantiateBlockTemplateRequest) (models.Block, error) {
+	t, err := s.BlockTemplates.Get(ctx, orgID, in.TemplateID)
+	if err != nil {
+		return models.Block{}, err
+	}
+	start, err := clockMinutes(in.StartTime)
+	if err != nil {
+		return models.Block{}, err
+	}
+	// Items may come before the block start, but not before the start of its day.
+	earliest := 0
+	for _, it := range t.Items {
+		earliest = min(earliest, it.OffsetMinutes)
+	}
+	if start+earliest < 0 {
+		var v validator
+		v.add("startTime", CodeOutOfRange, "startTime must be "+formatClock(-earliest)+" or later: the template has schedule items before the block start")
+		return models.Block{}, v.err()
+	}
+	b := models.Block{
+		DayID:        dayID,
+		Type:         t.Type,
+		Title:        t.Title,
+		Description:  t.Description,
+		StartTime:    formatClock(start),
+		EndTimeFixed: t.EndTimeFixed,
+		LocationID:   t.LocationID,
+		Notes:        t.Notes,
+	}
+	if t.DurationMinutes != nil {
+		b.EndTime = formatClock(start + *t.DurationMinutes)
+		b.EndDayOffset = dayOffset(start + *t.DurationMinutes)
+	}
+	for _, it := range t.Items {
+		b.ScheduleItems = append(b.ScheduleItems, models.ScheduleItem{
+			Time:              formatClock(start + it.OffsetMinutes),
+			DayOffset:         dayOffset(start + it.OffsetMinutes),
+			Description:       it.Description,
+			StaffInstructions: it.StaffInstructions,
+			GuestInstructions: it.GuestInstructions,
+			Notes:             it.Notes,
+		})
+	}
+	if err := s.ValidateBlock(ctx, orgID, &b); err != nil {
+		return models.Block{}, err
+	}
+	return s.Blocks.Create(ctx, orgID, b)
+}
+
+const minutesPerDay = 24 * 60
+
+// clockMinutes parses an HH:mm time into minutes after midnight.
+func clockMinutes(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q: %w", s, err)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// formatClock formats minutes after midnight as HH:mm, wrapping around the day.
+func formatClock(minutes int) string {
+	minutes = ((minutes % minutesPerDay) + minutesPerDay) % minutesPerDay
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// dayOffset returns how many days after the start day the given minutes (not
+// negative) fall on.
+func dayOffset(minutes int) int {
+	return minutes / minutesPerDay
+}
@@ -0,0 +1,540 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"planning-system/backend/internal/auth"
+	"planning-system/backend/internal/models"
+	"planning-system/backend/internal/repos"
+	"planning-system/backend/pkg/respond"
+)
+
+// Validation error codes.
+const (
+	CodeRequired      = "required"
+	CodeInvalidFormat = "invalid_format"
+	CodeInvalidValue  = "invalid_value"
+	CodeOutOfRange    = "out_of_range"
+	CodeNotFound      = "not_found"
+)
+
+// maxDayOffset bounds how many days after its own day an item may end.
+const maxDayOffset = 7
+
+// ValidationError lists every invalid field of a payload.
+type ValidationError struct {
+	Fields []respond.FieldError
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("validation failed: %d invalid field(s)", len(e.Fields))
+}
+
+// validator collects field errors, and the referenced IDs to look up, for one payload.
+type validator struct {
+	fields       []respond.FieldError
+	locations    []reference
+	vehicles     []reference
+	participants []reference
+}
+
+// reference is an ID found in the payload at field.
+type reference struct {
+	field string
+	id    string
+}
+
+func (v *validator) add(field, code, message string) {
+	v.fields = append(v.fields, respond.FieldError{Field: field, Code: code, Message: message})
+}
+
+// required reports whether value is set, recording an error if it is not.
+func (v *validator) required(field, value string) bool {
+	if value == "" {
+		v.add(field, CodeRequired, field+" is required")
+		return false
+	}
+	return true
+}
+
+// clock checks an optional HH:mm time.
+func (v *validator) clock(field, value string) {
+	if value == "" {
+		return
+	}
+	if _, err := time.Parse("15:04", value); err != nil {
+		v.add(field, CodeInvalidFormat, field+" must be HH:mm")
+	}
+}
+
+// date parses a required YYYY-MM-DD date.
+func (v *validator) date(field, value string) (time.Time, bool) {
+	if !v.required(field, value) {
+		return time.Time{}, false
+	}
+	d, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		v.add(field, CodeInvalidFormat, field+" must be YYYY-MM-DD")
+		return time.Time{}, false
+	}
+	return d, true
+}
+
+func (v *validator) oneOf(field, value string, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	msg := field + " must be one of"
+	for i, a := range allowed {
+		if i > 0 {
+			msg += ","
+		}
+		msg += " " + a
+	}
+	v.add(field, CodeInvalidValue, msg)
+}
+
+func (v *validator) between(field string, n, min, max int) {
+	if n < min || n > max {
+		v.add(field, CodeOutOfRange, fmt.Sprintf("%s must be between %d and %d", field, min, max))
+	}
+}
+
+// scheduleItem checks a schedule item; prefix is prepended to the field names.
+func (v *validator) scheduleItem(prefix string, si models.ScheduleItem) {
+	if v.required(prefix+"time", si.Time) {
+		v.clock(prefix+"time", si.Time)
+	}
+	v.required(prefix+"description", si.Description)
+	v.between(prefix+"dayOffset", si.DayOffset, 0, maxDayOffset)
+}
+
+func (v *validator) location(field string, id *string) {
+	if id != nil && *id != "" {
+		v.locations = append(v.locations, reference{field, *id})
+	}
+}
+
+func (v *validator) participantList(field string, ids []string) {
+	for i, id := range ids {
+		v.participants = append(v.participants, reference{fmt.Sprintf("%s[%d]", field, i), id})
+	}
+}
+
+// lookup records a not_found error for every collected reference that is not a row of the organization.
+func (v *validator) lookup(ctx context.Context, s *Services, orgID string) error {
+	checks := []struct {
+		refs    []reference
+		missing func(context.Context, string, []string) ([]string, error)
+		noun    string
+	}{
+		{v.locations, s.Locations.Missing, "location"},
+		{v.vehicles, s.Vehicles.Missing, "vehicle"},
+		{v.participants, s.Participants.Missing, "participant"},
+	}
+	for _, c := range checks {
+		if len(c.refs) == 0 {
+			continue
+		}
+		ids := make([]string, len(c.refs))
+		for i, r := range c.refs {
+			ids[i] = r.id
+		}
+		missing, err := c.missing(ctx, orgID, ids)
+		if err != nil {
+			return err
+		}
+		unknown := map[string]bool{}
+		for _, id := range missing {
+			unknown[id] = true
+		}
+		for _, r := range c.refs {
+			if unknown[r.id] {
+				v.add(r.field, CodeNotFound, c.noun+" "+r.id+" does not exist")
+			}
+		}
+	}
+	return nil
+}
+
+// err returns a *ValidationError if any field is invalid.
+func (v *validator) err() error {
+	if len(v.fields) > 0 {
+		return &ValidationError{Fields: v.fields}
+	}
+	return nil
+}
+
+// ValidateEvent checks an event payload. An empty time zone defaults to UTC.
+func ValidateEvent(in *models.Event) error {
+	var v validator
+	v.required("name", in.Name)
+	if in.TimeZone == "" {
+		in.TimeZone = "UTC"
+	}
+	if _, err := time.LoadLocation(in.TimeZone); err != nil {
+		v.add("timeZone", CodeInvalidValue, "timeZone must be an IANA time zone name")
+	}
+	start, okStart := v.date("startDate", in.StartDate)
+	end, okEnd := v.date("endDate", in.EndDate)
+	if okStart && okEnd && end.Before(start) {
+		v.add("endDate", CodeOutOfRange, "endDate must not be before startDate")
+	}
+	return v.err()
+}
+
+// ValidateCloneEvent checks the payload of an event clone.
+func ValidateCloneEvent(in models.CloneEventRequest) error {
+	var v validator
+	v.required("name", in.Name)
+	v.date("startDate", in.StartDate)
+	if in.TimeZone != "" {
+		if _, err := time.LoadLocation(in.TimeZone); err != nil {
+			v.add("timeZone", CodeInvalidValue, "timeZone must be an IANA time zone name")
+		}
+	}
+	return v.err()
+}
+
+// ValidateCreateDays checks a list of days to create.
+func ValidateCreateDays(in models.CreateDaysRequest) error {
+	var v validator
+	if len(in.Dates) == 0 {
+		v.add("dates", CodeRequired, "dates must not be empty")
+	}
+	for i, d := range in.Dates {
+		v.date(fmt.Sprintf("dates[%d]", i), d)
+	}
+	return v.err()
+}
+
+// ValidateDuplicateDay checks a day duplication request. An empty mode defaults to merge.
+func ValidateDuplicateDay(in *models.DuplicateDayRequest) error {
+	var v validator
+	if (in.TargetDayID == "") == (in.Date == "") {
+		v.add("targetDayId", CodeRequired, "exactly one of targetDayId or date is required")
+	} else if in.Date != "" {
+		v.date("date", in.Date)
+	}
+	if in.Mode == "" {
+		in.Mode = "merge"
+	}
+	v.oneOf("mode", in.Mode, "merge", "replace")
+	return v.err()
+}
+
+// ValidateLocation checks a location payload.
+func ValidateLocation(in models.Location) error {
+	var v validator
+	v.required("name", in.Name)
+	return v.err()
+}
+
+// ValidateParticipant checks a participant payload.
+func ValidateParticipant(in models.Participant) error {
+	var v validator
+	v.required("name", in.Name)
+	if in.Email != "" {
+		if _, err := mail.ParseAddress(in.Email); err != nil {
+			v.add("email", CodeInvalidFormat, "email must be an email address")
+		}
+	}
+	return v.err()
+}
+
+// ValidateVehicle checks a vehicle payload. Empty availability times and origination
+// location are cleared.
+func (s *Services) ValidateVehicle(ctx context.Context, orgID string, in *models.Vehicle) error {
+	var v validator
+	v.required("label", in.Label)
+	if in.Capacity != nil && *in.Capacity < 0 {
+		v.add("capacity", CodeOutOfRange, "capacity must not be negative")
+	}
+	for _, p := range []**string{&in.AvailableFrom, &in.AvailableTo, &in.OriginationLocationID} {
+		if *p != nil && **p == "" {
+			*p = nil
+		}
+	}
+	if in.AvailableFrom != nil {
+		v.clock("availableFrom", *in.AvailableFrom)
+	}
+	if in.AvailableTo != nil {
+		v.clock("availableTo", *in.AvailableTo)
+	}
+	v.location("originationLocationId", in.OriginationLocationID)
+	if err := v.lookup(ctx, s, orgID); err != nil {
+		return err
+	}
+	return v.err()
+}
+
+// ValidateBlock checks a block payload and fills in the day offsets clients may omit
+// (see normalizeBlockTimes). in.ID is the block being updated, empty for a new block:
+// schedule items keep their IDs, so an item with an ID must be an item of that block.
+func (s *Services) ValidateBlock(ctx context.Context, orgID string, in *models.Block) error {
+	var v validator
+	v.required("title", in.Title)
+	v.oneOf("type", in.Type, "activity", "break")
+	if v.required("startTime", in.StartTime) {
+		v.clock("startTime", in.StartTime)
+	}
+	v.clock("endTime", in.EndTime)
+	v.between("endDayOffset", in.EndDayOffset, 0, maxDayOffset)
+	var items []reference
+	seen := map[string]bool{}
+	for i, si := range in.ScheduleItems {
+		prefix := fmt.Sprintf("scheduleItems[%d].", i)
+		v.scheduleItem(prefix, si)
+		if si.ID == "" {
+			continue
+		}
+		if seen[si.ID] {
+			v.add(prefix+"id", CodeInvalidValue, "schedule item "+si.ID+" appears more than once")
+			continue
+		}
+		seen[si.ID] = true
+		items = append(items, reference{prefix + "id", si.ID})
+	}
+	v.location("locationId", in.LocationID)
+	v.participantList("participantsIds", in.ParticipantsIds)
+	v.participantList("advanceParticipantIds", in.AdvanceParticipantIDs)
+	v.participantList("metByParticipantIds", in.MetByParticipantIDs)
+	if err := v.lookup(ctx, s, orgID); err != nil {
+		return err
+	}
+	if len(items) > 0 {
+		ids := make([]string, len(items))
+		for i, r := range items {
+			ids[i] = r.id
+		}
+		missing, err := s.ScheduleItems.Missing(ctx, in.ID, ids)
+		if err != nil {
+			return err
+		}
+		unknown := map[string]bool{}
+		for _, id := range missing {
+			unknown[id] = true
+		}
+		for _, r := range items {
+			if unknown[r.id] {
+				v.add(r.field, CodeNotFound, "schedule item "+r.id+" is not an item of this block; leave id out to add an item")
+			}
+		}
+	}
+	if err := v.err(); err != nil {
+		return err
+	}
+	normalizeBlockTimes(in)
+	return nil
+}
+
+// ValidateScheduleItem checks a schedule item payload.
+func ValidateScheduleItem(in models.ScheduleItem) error {
+	var v validator
+	v.scheduleItem("", in)
+	return v.err()
+}
+
+// ValidateMoveScheduleItem checks a request to move a schedule item out of the block fromBlockID.
+func ValidateMoveScheduleItem(in models.MoveScheduleItemRequest, fromBlockID string) error {
+	var v validator
+	if v.required("blockId", in.BlockID) && in.BlockID == fromBlockID {
+		v.add("blockId", CodeInvalidValue, "blockId must be another block")
+	}
+	return v.err()
+}
+
+// ValidateMovement checks a movement payload and fills in the arrival day offset
+// clients may omit (see normalizeMovementTimes).
+func (s *Services) ValidateMovement(ctx context.Context, orgID string, in *models.Movement) error {
+	var v validator
+	v.required("title", in.Title)
+	if v.required("fromTime", in.FromTime) {
+		v.clock("fromTime", in.FromTime)
+	}
+	v.oneOf("toTimeType", in.ToTimeType, "fixed", "driving")
+	switch in.ToTimeType {
+	case "fixed":
+		if v.required("toTime", in.ToTime) {
+			v.clock("toTime", in.ToTime)
+		}
+		v.between("toDayOffset", in.ToDayOffset, 0, maxDayOffset)
+	case "driving":
+		if in.ToTime != "" {
+			if mins, err := strconv.Atoi(in.ToTime); err != nil || mins < 0 {
+				v.add("toTime", CodeInvalidFormat, "toTime must be the driving time in minutes")
+			}
+		} else if in.DrivingTimeHours == nil && in.DrivingTimeMinutes == nil {
+			v.add("toTime", CodeRequired, "toTime or drivingTimeHours/drivingTimeMinutes is required")
+		}
+		if in.DrivingTimeHours != nil && *in.DrivingTimeHours < 0 {
+			v.add("drivingTimeHours", CodeOutOfRange, "drivingTimeHours must not be negative")
+		}
+		if in.DrivingTimeMinutes != nil && *in.DrivingTimeMinutes < 0 {
+			v.add("drivingTimeMinutes", CodeOutOfRange, "drivingTimeMinutes must not be negative")
+		}
+	}
+	v.location("fromLocationId", &in.FromLocationID)
+	v.location("toLocationId", &in.ToLocationID)
+	for i, a := range in.VehicleAssignments {
+		prefix := fmt.Sprintf("vehicleAssignments[%d].", i)
+		if v.required(prefix+"vehicleId", a.VehicleID) {
+			v.vehicles = append(v.vehicles, reference{prefix + "vehicleId", a.VehicleID})
+		}
+		if a.DriverID != nil && *a.DriverID != "" {
+			v.participants = append(v.participants, reference{prefix + "driverId", *a.DriverID})
+		}
+		v.participantList(prefix+"participantIds", a.ParticipantIDs)
+	}
+	if err := v.lookup(ctx, s, orgID); err != nil {
+		return err
+	}
+	if err := v.err(); err != nil {
+		return err
+	}
+	normalizeMovementTimes(in)
+	return nil
+}
+
+// ValidateBlockTemplate checks a block template payload.
+func (s *Services) ValidateBlockTemplate(ctx context.Context, orgID string, in models.BlockTemplate) error {
+	var v validator
+	v.required("name", in.Name)
+	v.required("title", in.Title)
+	v.oneOf("type", in.Type, "activity", "break")
+	if in.DurationMinutes != nil && *in.DurationMinutes < 0 {
+		v.add("durationMinutes", CodeOutOfRange, "durationMinutes must not be negative")
+	}
+	for i, it := range in.Items {
+		v.required(fmt.Sprintf("items[%d].description", i), it.Description)
+	}
+	v.location("locationId", in.LocationID)
+	if err := v.lookup(ctx, s, orgID); err != nil {
+		return err
+	}
+	return v.err()
+}
+
+// ValidateInstantiateBlockTemplate checks a request to create a block from a template.
+func ValidateInstantiateBlockTemplate(in models.InstantiateBlockTemplateRequest) error {
+	var v validator
+	v.required("templateId", in.TemplateID)
+	if v.required("startTime", in.StartTime) {
+		v.clock("startTime", in.StartTime)
+	}
+	return v.err()
+}
+
+// ValidateSaveBlockTemplate checks a request to save a block as a template.
+func ValidateSaveBlockTemplate(in models.SaveBlockTemplateRequest) error {
+	var v validator
+	v.required("name", in.Name)
+	return v.err()
+}
+
+// ValidateEventMember checks an event membership payload.
+func ValidateEventMember(in models.PutEventMemberRequest) error {
+	var v validator
+	v.oneOf("role", in.Role, auth.EventRolePlanner, auth.EventRoleViewer)
+	return v.err()
+}
+
+// ValidateIssueAccessToken checks a participant access token request; 0 hours means the default.
+func ValidateIssueAccessToken(in models.IssueAccessTokenRequest) error {
+	var v validator
+	v.between("expiresInHours", in.ExpiresInHours, 0, maxAccessTokenHours)
+	return v.err()
+}
+
+// ValidateAPIKey checks an API key request and removes duplicate scopes.
+func ValidateAPIKey(in *models.CreateAPIKeyRequest) error {
+	var v validator
+	in.Name = strings.TrimSpace(in.Name)
+	v.required("name", in.Name)
+	if len(in.Scopes) == 0 {
+		v.add("scopes", CodeRequired, "at least one scope is required")
+	}
+	seen := map[string]bool{}
+	scopes := make([]string, 0, len(in.Scopes))
+	for i, scope := range in.Scopes {
+		if !auth.ValidAPIKeyScope(scope) {
+			v.add(fmt.Sprintf("scopes[%d]", i), CodeInvalidValue, "unknown scope: "+scope)
+			continue
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	in.Scopes = scopes
+	if in.ExpiresAt != nil && !in.ExpiresAt.After(time.Now()) {
+		v.add("expiresAt", CodeOutOfRange, "expiresAt must be in the future")
+	}
+	return v.err()
+}
+
+// ValidateDeleteOptions checks the options of deleting the location, vehicle or participant id.
+func ValidateDeleteOptions(id string, opts repos.DeleteOptions) error {
+	var v validator
+	if opts.ReplaceWith != "" {
+		switch {
+		case opts.Force:
+			v.add("replaceWith", CodeInvalidValue, "replaceWith cannot be combined with force")
+		case uuid.Validate(opts.ReplaceWith) != nil:
+			v.add("replaceWith", CodeInvalidFormat, "replaceWith must be an ID")
+		case strings.EqualFold(opts.ReplaceWith, id):
+			v.add("replaceWith", CodeInvalidValue, "replaceWith must differ from the deleted record")
+		}
+	}
+	return v.err()
+}
+
+// normalizeBlockTimes fills in the day offsets of a block and its schedule items.
+// Clients that do not send offsets get the previous behaviour: an end time before
+// the start time falls on the next day, and so do schedule items before the start
+// of such an overnight block.
+func normalizeBlockTimes(in *models.Block) {
+	if in.EndDayOffset == 0 && clockBefore(in.EndTime, in.StartTime) {
+		in.EndDayOffset = 1
+	}
+	for i := range in.ScheduleItems {
+		si := &in.ScheduleItems[i]
+		if si.DayOffset == 0 && in.EndDayOffset > 0 && clockBefore(si.Time, in.StartTime) {
+			si.DayOffset = 1
+		}
+	}
+}
+
+// normalizeMovementTimes fills in the arrival day offset of a fixed-time movement:
+// without one, an arrival before the departure falls on the next day. Driving
+// movements derive their arrival from the driving time and carry no offset.
+func normalizeMovementTimes(in *models.Movement) {
+	if in.ToTimeType != "fixed" {
+		in.ToDayOffset = 0
+		return
+	}
+	if in.ToDayOffset == 0 && clockBefore(in.ToTime, in.FromTime) {
+		in.ToDayOffset = 1
+	}
+}
+
+// clockBefore reports whether the HH:mm time a is earlier than b. Unparseable values compare false.
+func clockBefore(a, b string) bool {
+	ta, err := time.Parse("15:04", a)
+	if err != nil {
+		return false
+	}
+	tb, err := time.Parse("15:04", b)
+	if err != nil {
+		return false
+	}
+	return ta.Before(tb)
+}
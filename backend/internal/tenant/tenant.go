@@ -0,0 +1,16 @@
+package tenant
+
+import "context"
+
+type ctxKey struct{}
+
+// WithOrganization returns a copy of ctx carrying the caller's organization ID.
+func WithOrganization(ctx context.Context, orgID string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, orgID)
+}
+
+// OrganizationID returns the organization ID stored in ctx, or "" if none is set.
+func OrganizationID(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
@@ -18,6 +18,29 @@ type errorResponse struct {
 	Error string `json:"error"`
 }
 
+// FieldError describes one invalid field of a request payload. Field is the JSON
+// path of the value, e.g. "scheduleItems[2].time".
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type staleResponse[T any] struct {
+	Error string `json:"error"`
+	Item  T      `json:"item"`
+}
+
+type conflictResponse[T any] struct {
+	Error      string `json:"error"`
+	References []T    `json:"references"`
+}
+
+type validationResponse struct {
+	Error  string       `json:"error"`
+	Fields []FieldError `json:"fields"`
+}
+
 func JSON(w http.ResponseWriter, status int, v any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -28,6 +51,21 @@ func Error(w http.ResponseWriter, status int, message string) {
 	JSON(w, status, errorResponse{Error: message})
 }
 
+// Invalid answers 422 Unprocessable Entity listing every invalid field.
+func Invalid(w http.ResponseWriter, fields []FieldError) {
+	JSON(w, http.StatusUnprocessableEntity, validationResponse{Error: "validation failed", Fields: fields})
+}
+
+// PreconditionFailed answers 412 Precondition Failed with the current state of the item.
+func PreconditionFailed[T any](w http.ResponseWriter, message string, current T) {
+	JSON(w, http.StatusPreconditionFailed, staleResponse[T]{Error: message, Item: current})
+}
+
+// InUse answers 409 Conflict listing the records that still reference the item.
+func InUse[T any](w http.ResponseWriter, message string, references []T) {
+	JSON(w, http.StatusConflict, conflictResponse[T]{Error: message, References: references})
+}
+
 func List[T any](w http.ResponseWriter, status int, items []T, total *int64) {
 	JSON(w, status, listResponse[T]{Items: items, Total: total})
 }